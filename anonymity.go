@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//AnonymityLevel defines how much information about the client is leaked by the proxy.
+//Higher values mean better anonymity.
+type AnonymityLevel int
+
+//Anonymity levels
+const (
+	//AnonTransparent - your ip is known, proxy usage is known
+	AnonTransparent AnonymityLevel = iota
+	//AnonHiddenProxy - your ip is known, proxy usage is unknown
+	AnonHiddenProxy
+	//AnonAnonymous - your ip is unknown, proxy usage is known
+	AnonAnonymous
+	//AnonElite - your ip is unknown, proxy usage is unknown
+	AnonElite
+)
+
+var anonymityLevelNames = map[AnonymityLevel]string{
+	AnonTransparent: "transparent",
+	AnonHiddenProxy: "hidden-proxy",
+	AnonAnonymous:   "anonymous",
+	AnonElite:       "elite",
+}
+
+var anonymityLevelDescriptions = map[AnonymityLevel]string{
+	AnonTransparent: "shows real ip, shows proxy usage",
+	AnonHiddenProxy: "shows real ip, hides proxy usage",
+	AnonAnonymous:   "doesn't show real ip, shows proxy usage",
+	AnonElite:       "doesn't show real ip, doesn't show proxy usage",
+}
+
+//NewAnonymityLevel returns anonymity level matching given observations
+func NewAnonymityLevel(showsRealIP, showsProxyUsage bool) AnonymityLevel {
+	switch {
+	case showsRealIP && showsProxyUsage:
+		return AnonTransparent
+	case showsRealIP:
+		return AnonHiddenProxy
+	case showsProxyUsage:
+		return AnonAnonymous
+	}
+	return AnonElite
+}
+
+//ParseAnonymityLevel converts level name (or its numeric value) to an AnonymityLevel
+func ParseAnonymityLevel(name string) (AnonymityLevel, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if n, err := strconv.Atoi(name); err == nil {
+		if !AnonymityLevel(n).IsValid() {
+			return AnonTransparent, fmt.Errorf("invalid anonymity level: %d", n)
+		}
+		return AnonymityLevel(n), nil
+	}
+	for level, levelName := range anonymityLevelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+	return AnonTransparent, fmt.Errorf("unknown anonymity level: %s", name)
+}
+
+//String returns the name of the level
+func (a AnonymityLevel) String() string {
+	if name, ok := anonymityLevelNames[a]; ok {
+		return name
+	}
+	return "AnonymityLevel(" + strconv.Itoa(int(a)) + ")"
+}
+
+//Description returns human readable description of the level
+func (a AnonymityLevel) Description() string {
+	return anonymityLevelDescriptions[a]
+}
+
+//IsValid returns true if the level is one of the known values
+func (a AnonymityLevel) IsValid() bool {
+	_, ok := anonymityLevelNames[a]
+	return ok
+}
+
+//AtLeast returns true if the level is equal or better than the given one
+func (a AnonymityLevel) AtLeast(level AnonymityLevel) bool {
+	return a >= level
+}
+
+//HidesRealIP returns true if the client ip is not visible to the target
+func (a AnonymityLevel) HidesRealIP() bool {
+	return a == AnonAnonymous || a == AnonElite
+}
+
+//HidesProxyUsage returns true if the target can't tell that a proxy is used
+func (a AnonymityLevel) HidesProxyUsage() bool {
+	return a == AnonHiddenProxy || a == AnonElite
+}
+
+//MarshalJSON implements json.Marshaler. Levels are encoded as numbers (0..3) for backward
+//compatibility, see Judgement.MarshalJSONNames for the names.
+func (a AnonymityLevel) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(a))), nil
+}
+
+//UnmarshalJSON implements json.Unmarshaler. Both numbers and names are accepted.
+func (a *AnonymityLevel) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "null" {
+		return nil
+	}
+	level, err := ParseAnonymityLevel(text)
+	if err != nil {
+		return err
+	}
+	*a = level
+	return nil
+}
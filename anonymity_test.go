@@ -0,0 +1,55 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAnonymityLevel(t *testing.T) {
+	assert.Equal(t, AnonTransparent, NewAnonymityLevel(true, true))
+	assert.Equal(t, AnonHiddenProxy, NewAnonymityLevel(true, false))
+	assert.Equal(t, AnonAnonymous, NewAnonymityLevel(false, true))
+	assert.Equal(t, AnonElite, NewAnonymityLevel(false, false))
+
+	assert.True(t, AnonElite.AtLeast(AnonAnonymous))
+	assert.True(t, AnonAnonymous.AtLeast(AnonAnonymous))
+	assert.False(t, AnonHiddenProxy.AtLeast(AnonAnonymous))
+	assert.True(t, AnonAnonymous.HidesRealIP())
+	assert.False(t, AnonAnonymous.HidesProxyUsage())
+}
+
+func TestParseAnonymityLevel(t *testing.T) {
+	for name, expected := range map[string]AnonymityLevel{"elite": AnonElite, "Anonymous": AnonAnonymous, "1": AnonHiddenProxy} {
+		level, err := ParseAnonymityLevel(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, level)
+	}
+	_, err := ParseAnonymityLevel("7")
+	assert.Error(t, err)
+	_, err = ParseAnonymityLevel("unknown")
+	assert.Error(t, err)
+}
+
+func TestAnonymityLevel_JSON(t *testing.T) {
+	judgement := &Judgement{AnonType: AnonElite}
+	encoded, err := judgement.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"anon_type":3`, "numbers should be used by default")
+
+	encoded, err = judgement.MarshalJSONNames()
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"anon_type":"elite"`)
+	assert.Equal(t, 1, strings.Count(string(encoded), "anon_type"))
+
+	judgement.AnonType = AnonymityLevel(7)
+	_, err = judgement.MarshalJSONNames()
+	assert.Error(t, err)
+
+	for _, data := range []string{`{"anon_type":2}`, `{"anon_type":"anonymous"}`} {
+		decoded := &Judgement{}
+		assert.NoError(t, decoded.UnmarshalJSON([]byte(data)))
+		assert.Equal(t, AnonAnonymous, decoded.AnonType)
+	}
+}
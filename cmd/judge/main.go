@@ -1,31 +1,250 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/alekc/proxy/judge"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 var (
-	listenAddress = kingpin.Flag("listenAddress", "Listen Address.").Short('l').Default(":8080").String()
+	listenAddress = kingpin.Flag("listenAddress", "Listen Address (empty to serve only tls).").Short('l').Default(":8080").String()
 	_             = kingpin.Flag("debug", "Debug Output.").Short('d').Bool()
 	cfSupport     = kingpin.Flag("cloudflare", "Enable cloudflare support.").Short('c').Default("false").Bool()
-	trustedGw     = kingpin.Flag("gw", "Trusted gateways which add via headers separated by commas").Short('g').Default("").String()
+	cfRanges      = kingpin.Flag("cloudflare-ranges", "File or url with cloudflare networks, can be repeated (i.e. https://www.cloudflare.com/ips-v4). Embedded list is used if not set.").Strings()
+	cfRefresh     = kingpin.Flag("cloudflare-refresh", "Refresh interval of cloudflare (or edge) networks.").Default("24h").Duration()
+	edgeName      = kingpin.Flag("edge", "Edge network in front of the judge ("+strings.Join(judge.EdgeNames(), ", ")+"). Overrides --cloudflare.").Default("").Enum(append(judge.EdgeNames(), "")...)
+	edgeRanges    = kingpin.Flag("edge-ranges", "File or url with networks of the edge servers, can be repeated.").Strings()
+	edgeIPHeader  = kingpin.Flag("edge-ip-header", "Header with the client ip set by the edge (overrides the edge default).").Default("").String()
+	edgeCountry   = kingpin.Flag("edge-country-header", "Header with the client country set by the edge (overrides the edge default).").Default("").String()
+	edgeIgnored   = kingpin.Flag("edge-ignore-headers", "Headers added by the edge separated by commas (added to the edge defaults).").Default("").String()
+	fingerprints  = kingpin.Flag("fingerprints", "Json file with additional proxy software fingerprints.").Default("").String()
+	rulesFile     = kingpin.Flag("rules", "Yaml or json file with header and hostname rules (reloaded on change and SIGHUP). Built in rules are used if not set.").Default("").String()
+	rulesWatch    = kingpin.Flag("rules-watch", "Interval of checking the rules file for changes, 0 disables watching.").Default("10s").Duration()
+	geoipFiles    = kingpin.Flag("geoip", "GeoIP database (mmdb or csv ranges) with country, city or asn of the proxy, can be repeated. Reloaded on change and SIGHUP.").Strings()
+	geoipRefresh  = kingpin.Flag("geoip-refresh", "Interval of checking geoip databases for changes, 0 disables watching.").Default("1m").Duration()
+	dcRanges      = kingpin.Flag("datacenter-ranges", "File or url with datacenter networks (cidrs or ips), can be repeated.").Strings()
+	torExits      = kingpin.Flag("tor-exits", "File or url with tor exit nodes (ips or tor exit list), can be repeated.").Strings()
+	vpnRanges     = kingpin.Flag("vpn-ranges", "File or url with vpn networks (cidrs or ips), can be repeated.").Strings()
+	classRefresh  = kingpin.Flag("class-refresh", "Refresh interval of datacenter, tor and vpn lists (also reloaded on SIGHUP).").Default("1h").Duration()
+	trustedGw     = kingpin.Flag("gw", "Trusted gateways (ips or cidrs) which add via headers separated by commas").Short('g').Default("").String()
+	trustedHops   = kingpin.Flag("trusted-hops", "Number of gateways in front of the judge trusted regardless of their ip.").Default("0").Int()
+	anonNames     = kingpin.Flag("anon-names", "Encode anonymity level as a name (elite, anonymous...) instead of a number.").Default("false").Bool()
+	readTimeout   = kingpin.Flag("read-timeout", "Maximum duration for reading the entire request.").Default("10s").Duration()
+	headerTimeout = kingpin.Flag("header-timeout", "Maximum duration for reading request headers.").Default("5s").Duration()
+	writeTimeout  = kingpin.Flag("write-timeout", "Maximum duration before timing out writes of the response.").Default("10s").Duration()
+	maxHeaderSize = kingpin.Flag("max-header-size", "Maximum size of request headers in bytes.").Default("65536").Int()
+	tlsAddress    = kingpin.Flag("tls-listen", "Tls listen address, tls is disabled if empty.").Default("").String()
+	tlsCert       = kingpin.Flag("tls-cert", "Tls certificate file (reloaded on SIGHUP).").Default("").String()
+	tlsKey        = kingpin.Flag("tls-key", "Tls private key file (reloaded on SIGHUP).").Default("").String()
+	tlsSelfSigned = kingpin.Flag("tls-self-signed", "Generate a self signed certificate on startup instead of using cert/key files.").Default("false").Bool()
+	tlsHosts      = kingpin.Flag("tls-hosts", "Hosts and ips of the self signed certificate separated by commas.").Default("localhost,127.0.0.1,::1").String()
 )
 
 func main() {
 	kingpin.Parse()
+	if *listenAddress == "" && *tlsAddress == "" {
+		kingpin.Fatalf("at least one of listenAddress and tls-listen has to be set")
+	}
+	if _, err := judge.ParseNetworks(strings.Split(*trustedGw, ",")); err != nil {
+		kingpin.Fatalf("invalid trusted gateway: %s", err)
+	}
+	fingerprintTable := judge.DefaultFingerprintTable()
+	if *fingerprints != "" {
+		var err error
+		if fingerprintTable, err = judge.LoadFingerprints(*fingerprints, true); err != nil {
+			kingpin.Fatalf("couldn't load fingerprints: %s", err)
+		}
+	}
+	rules := judge.DefaultRuleSet()
+	if *rulesFile != "" {
+		var err error
+		if rules, err = judge.LoadRules(*rulesFile); err != nil {
+			kingpin.Fatalf("couldn't load rules: %s", err)
+		}
+	}
+	var geoIP *judge.GeoIP
+	if len(*geoipFiles) > 0 {
+		geoIP = judge.NewGeoIP(*geoipFiles...)
+		geoIP.Interval = *geoipRefresh
+		if err := geoIP.Load(); err != nil {
+			kingpin.Fatalf("couldn't load geoip database: %s", err)
+		}
+	}
+	classifier := newClassifier()
+
+	//tls configuration
+	var tlsConfig *tls.Config
+	var certLoader *judge.CertificateLoader
+	if *tlsAddress != "" {
+		switch {
+		case *tlsSelfSigned:
+			cert, err := judge.SelfSignedCertificate(strings.Split(*tlsHosts, ",")...)
+			if err != nil {
+				kingpin.Fatalf("couldn't generate certificate: %s", err)
+			}
+			tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		case *tlsCert != "" && *tlsKey != "":
+			var err error
+			if certLoader, err = judge.NewCertificateLoader(*tlsCert, *tlsKey); err != nil {
+				kingpin.Fatalf("couldn't load certificate: %s", err)
+			}
+			tlsConfig = certLoader.TLSConfig()
+		default:
+			kingpin.Fatalf("tls requires tls-cert and tls-key or tls-self-signed")
+		}
+	}
+
+	//plain and tls judges are independent, the first failure stops both
+	judges := make([]*judge.Judge, 0, 2)
+	serve := make([]func(ctx context.Context) error, 0, 2)
+	if *listenAddress != "" {
+		plain := newJudge(*listenAddress, fingerprintTable, rules, geoIP, classifier)
+		judges = append(judges, plain)
+		serve = append(serve, plain.ListenAndServe)
+	}
+	if tlsConfig != nil {
+		secure := newJudge(*tlsAddress, fingerprintTable, rules, geoIP, classifier)
+		judges = append(judges, secure)
+		serve = append(serve, func(ctx context.Context) error {
+			return secure.ListenAndServeTLS(ctx, tlsConfig)
+		})
+	}
+
+	//stop gracefully on interrupt, reload certificates, rules, geoip databases and ip lists on hangup
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	go func() {
+		for sig := range signals {
+			if sig != syscall.SIGHUP {
+				cancel()
+				return
+			}
+			if *rulesFile != "" {
+				for _, j := range judges {
+					_ = j.ReloadRules(*rulesFile)
+				}
+			}
+			if geoIP != nil {
+				_ = geoIP.Load()
+			}
+			if classifier != nil {
+				_ = classifier.Load(ctx)
+			}
+			if certLoader == nil {
+				continue
+			}
+			if err := certLoader.Reload(); err != nil {
+				fmt.Fprintf(os.Stderr, "couldn't reload certificate: %s\n", err)
+			}
+		}
+	}()
+	if *rulesFile != "" && *rulesWatch > 0 {
+		for _, j := range judges {
+			go j.WatchRules(ctx, *rulesFile, *rulesWatch)
+		}
+	}
+	if geoIP != nil {
+		go geoIP.Run(ctx)
+	}
+	if classifier != nil {
+		go classifier.Run(ctx)
+	}
+
+	//start
+	errs := make(chan error, len(serve))
+	for _, fn := range serve {
+		go func(fn func(ctx context.Context) error) {
+			errs <- fn(ctx)
+		}(fn)
+	}
+	for range serve {
+		if err := <-errs; err != nil {
+			cancel()
+			kingpin.Fatalf("judge failed: %s", err)
+		}
+		cancel()
+	}
+}
 
-	//Starting & Configuration
+//newJudge creates judge configured from the flags
+func newJudge(address string, fingerprints *judge.FingerprintTable, rules *judge.RuleSet, geoIP *judge.GeoIP, classifier *judge.Classifier) *judge.Judge {
 	pJudge := judge.Create()
-	pJudge.ListenAddress = *listenAddress
+	pJudge.ListenAddress = address
+	pJudge.Fingerprints = fingerprints
+	pJudge.SetRules(rules)
+	pJudge.GeoIP = geoIP
+	pJudge.Classifier = classifier
 	//pJudge.DebugEnabled = *debugEnabled
 	pJudge.CloudFlareSupport = *cfSupport
+	pJudge.CloudflareRanges.Sources = *cfRanges
+	pJudge.CloudflareRanges.Interval = *cfRefresh
+	if *edgeName != "" {
+		pJudge.Edge = newEdge()
+	}
 	if len(*trustedGw) > 0 {
 		pJudge.TrustedGatewaysIps = strings.Split(*trustedGw, ",")
 	}
+	pJudge.TrustedHops = *trustedHops
+	pJudge.AnonymityNames = *anonNames
 
-	//start
-	pJudge.Start()
+	pJudge.ReadTimeout = *readTimeout
+	pJudge.ReadHeaderTimeout = *headerTimeout
+	pJudge.WriteTimeout = *writeTimeout
+	pJudge.MaxHeaderBytes = *maxHeaderSize
+	return pJudge
+}
+
+//newEdge creates edge configured from the flags
+func newEdge() judge.Edge {
+	edge, err := judge.NewEdge(*edgeName)
+	if err != nil {
+		kingpin.Fatalf("%s", err)
+	}
+	edge.Ranges.Sources = append(edge.Ranges.Sources, *edgeRanges...)
+	if edge.Name() == "cloudflare" {
+		edge.Ranges.Sources = append(edge.Ranges.Sources, *cfRanges...)
+	}
+	edge.Ranges.Interval = *cfRefresh
+	if *edgeIPHeader != "" {
+		edge.ClientHeader = *edgeIPHeader
+	}
+	if *edgeCountry != "" {
+		edge.Country = *edgeCountry
+	}
+	for _, header := range strings.Split(*edgeIgnored, ",") {
+		if header = strings.TrimSpace(header); header != "" {
+			edge.Ignored = append(edge.Ignored, header)
+		}
+	}
+	return edge
+}
+
+//newClassifier creates classifier of the ip lists set by the flags, nil if there are no lists
+func newClassifier() *judge.Classifier {
+	lists := map[string][]string{
+		judge.CategoryDatacenter: *dcRanges,
+		judge.CategoryTor:        *torExits,
+		judge.CategoryVPN:        *vpnRanges,
+	}
+	classifier := judge.NewClassifier()
+	for _, category := range []string{judge.CategoryDatacenter, judge.CategoryTor, judge.CategoryVPN} {
+		if len(lists[category]) > 0 {
+			classifier.Add(category, lists[category]...).Interval = *classRefresh
+		}
+	}
+	if len(classifier.Categories()) == 0 {
+		return nil
+	}
+	if err := classifier.Load(context.Background()); err != nil {
+		kingpin.Fatalf("couldn't load ip lists: %s", err)
+	}
+	return classifier
 }
@@ -0,0 +1,16 @@
+package proxy
+
+//ListEntry is a single element of a proxy list, optionally accompanied by the latest judgement
+//easyjson:json
+type ListEntry struct {
+	Proxy     *Proxy     `json:"proxy"`
+	Judgement *Judgement `json:"judgement,omitempty"`
+}
+
+//listEntryLine is the json lines representation of a ListEntry. Proxy fields are inlined
+//so the output can be read back by ListReader.
+type listEntryLine struct {
+	Proxy
+	Password  string     `json:"password,omitempty"`
+	Judgement *Judgement `json:"judgement,omitempty"`
+}
@@ -0,0 +1,277 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package proxy
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson5f4debdaDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *listEntryLine) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "password":
+			out.Password = string(in.String())
+		case "judgement":
+			if in.IsNull() {
+				in.Skip()
+				out.Judgement = nil
+			} else {
+				if out.Judgement == nil {
+					out.Judgement = new(Judgement)
+				}
+				(*out.Judgement).UnmarshalEasyJSON(in)
+			}
+		case "ip":
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.IP).UnmarshalText(data))
+			}
+		case "host":
+			out.Host = string(in.String())
+		case "port":
+			out.Port = int(in.Int())
+		case "type":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Type).UnmarshalJSON(data))
+			}
+		case "username":
+			out.Username = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson5f4debdaEncodeGithubComAlekcProxy(out *jwriter.Writer, in listEntryLine) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Password != "" {
+		const prefix string = ",\"password\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Password))
+	}
+	if in.Judgement != nil {
+		const prefix string = ",\"judgement\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Judgement).MarshalEasyJSON(out)
+	}
+	if len(in.IP) != 0 {
+		const prefix string = ",\"ip\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.RawText((in.IP).MarshalText())
+	}
+	if in.Host != "" {
+		const prefix string = ",\"host\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Host))
+	}
+	{
+		const prefix string = ",\"port\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Port))
+	}
+	if in.Type != 0 {
+		const prefix string = ",\"type\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.RawText((in.Type).MarshalText())
+	}
+	if in.Username != "" {
+		const prefix string = ",\"username\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Username))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v listEntryLine) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson5f4debdaEncodeGithubComAlekcProxy(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v listEntryLine) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson5f4debdaEncodeGithubComAlekcProxy(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *listEntryLine) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson5f4debdaDecodeGithubComAlekcProxy(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *listEntryLine) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson5f4debdaDecodeGithubComAlekcProxy(l, v)
+}
+func easyjson5f4debdaDecodeGithubComAlekcProxy1(in *jlexer.Lexer, out *ListEntry) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "proxy":
+			if in.IsNull() {
+				in.Skip()
+				out.Proxy = nil
+			} else {
+				if out.Proxy == nil {
+					out.Proxy = new(Proxy)
+				}
+				(*out.Proxy).UnmarshalEasyJSON(in)
+			}
+		case "judgement":
+			if in.IsNull() {
+				in.Skip()
+				out.Judgement = nil
+			} else {
+				if out.Judgement == nil {
+					out.Judgement = new(Judgement)
+				}
+				(*out.Judgement).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson5f4debdaEncodeGithubComAlekcProxy1(out *jwriter.Writer, in ListEntry) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"proxy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Proxy == nil {
+			out.RawString("null")
+		} else {
+			(*in.Proxy).MarshalEasyJSON(out)
+		}
+	}
+	if in.Judgement != nil {
+		const prefix string = ",\"judgement\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Judgement).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ListEntry) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson5f4debdaEncodeGithubComAlekcProxy1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ListEntry) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson5f4debdaEncodeGithubComAlekcProxy1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ListEntry) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson5f4debdaDecodeGithubComAlekcProxy1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ListEntry) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson5f4debdaDecodeGithubComAlekcProxy1(l, v)
+}
@@ -0,0 +1,233 @@
+package extract
+
+import (
+	"html"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alekc/proxy"
+	"github.com/pkg/errors"
+)
+
+//maximum size of the fetched page
+const maxPageSize = 16 * 1024 * 1024
+
+var (
+	//hidden elements are a common obfuscation technique (<span style="display:none">12</span>)
+	hiddenRegex = regexp.MustCompile(`(?is)<([a-z0-9]+)[^>]*(?:display\s*:\s*none|visibility\s*:\s*hidden)[^>]*>.*?</([a-z0-9]+)>`)
+	//script, style and comments never contain visible proxies
+	invisibleRegex = regexp.MustCompile(`(?is)<script[^>]*>.*?</script>|<style[^>]*>.*?</style>|<!--.*?-->`)
+	//block elements separate the text, table cells are converted to tabs
+	cellRegex  = regexp.MustCompile(`(?i)</?(?:td|th)(?:\s[^>]*)?>`)
+	blockRegex = regexp.MustCompile(`(?i)</?(?:tr|table|thead|tbody|br|p|div|li|ul|ol|pre|h[1-6])(?:\s[^>]*)?/?>`)
+	//remaining (inline) tags are removed without leaving a separator, so 1.2.3.4<span>:</span>8080 is joined
+	tagRegex        = regexp.MustCompile(`(?s)<[^>]*>`)
+	whitespaceRegex = regexp.MustCompile(`\s+`)
+
+	ipv4Regex = regexp.MustCompile(`(\d{1,3}(?:\.\d{1,3}){3})(?:[ ]*:[ ]*|\t+)(\d{1,5})\b`)
+	ipv6Regex = regexp.MustCompile(`\[([0-9a-fA-F:.]+)\][ ]*:[ ]*(\d{1,5})\b`)
+	//ipv6 address and port in separate table cells
+	ipv6CellRegex = regexp.MustCompile(`(?m)(?:^|\t)([0-9a-fA-F]*:[0-9a-fA-F:.]*:[0-9a-fA-F.]*)\t+(\d{1,5})\b`)
+	hostRegex     = regexp.MustCompile(`(?i)\b((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,63})(?:[ ]*:[ ]*|\t+)(\d{1,5})\b`)
+	//url scheme directly preceding the host (socks5://1.2.3.4:1080)
+	schemeRegex = regexp.MustCompile(`(?i)\b([a-z][a-z0-9]*)://\[?$`)
+)
+
+//Extract finds proxies in the content. Html is detected automatically. Source is recorded
+//in every returned proxy.
+func Extract(content, source string) []*proxy.Proxy {
+	if looksLikeHTML(content) {
+		return ExtractHTML(content, source)
+	}
+	return ExtractText(content, source)
+}
+
+//ExtractHTML finds proxies in the html page, handling tables and inline obfuscation
+func ExtractHTML(content, source string) []*proxy.Proxy {
+	return ExtractText(htmlToText(content), source)
+}
+
+//ExtractText finds ip:port, [ipv6]:port and host:port pairs in the text. Type of the proxy is
+//set from the url scheme preceding the pair (i.e. socks5://1.2.3.4:1080), if any.
+//Returned proxies are deduplicated and ordered by their first occurrence.
+func ExtractText(content, source string) []*proxy.Proxy {
+	found := make([]match, 0)
+	add := func(m match, px *proxy.Proxy, err error) {
+		if err != nil {
+			return
+		}
+		if proxyType, err := proxy.ParseProxyType(m.scheme); err == nil {
+			px.Type = proxyType
+		}
+		m.proxy = px
+		found = append(found, m)
+	}
+	for _, m := range findAll(ipv4Regex, content) {
+		px, err := proxy.FromIpv4String(m.host + ":" + m.port)
+		add(m, px, err)
+	}
+	for _, m := range append(findAll(ipv6Regex, content), findAll(ipv6CellRegex, content)...) {
+		px, err := parseIPv6(m.host, m.port)
+		add(m, px, err)
+	}
+	for _, m := range findAll(hostRegex, content) {
+		px, err := proxy.Parse(m.host + ":" + m.port)
+		add(m, px, err)
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].position < found[j].position
+	})
+	result := make([]*proxy.Proxy, 0, len(found))
+	seen := make(map[string]bool)
+	//addresses listed with a scheme, and those listed only without one
+	typed := make(map[string]bool)
+	untyped := make(map[string]*proxy.Proxy)
+	for _, m := range found {
+		address := m.proxy.ToString()
+		if m.proxy.Type == proxy.TypeUnknown {
+			//the same address can be listed with and without the scheme
+			if typed[address] || untyped[address] != nil {
+				continue
+			}
+			untyped[address] = m.proxy
+		} else {
+			if seen[m.proxy.Key()] {
+				continue
+			}
+			seen[m.proxy.Key()] = true
+			typed[address] = true
+			if px := untyped[address]; px != nil {
+				px.Type = m.proxy.Type
+				delete(untyped, address)
+				continue
+			}
+		}
+		m.proxy.Source = source
+		result = append(result, m.proxy)
+	}
+	return result
+}
+
+//match is a host/port candidate found in the text
+type match struct {
+	host string
+	port string
+	//url scheme preceding the host, empty if there is none
+	scheme   string
+	position int
+	proxy    *proxy.Proxy
+}
+
+//Fetch downloads the page and extracts proxies from it
+func Fetch(client *http.Client, url string) ([]*proxy.Proxy, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d fetching %s", resp.StatusCode, url)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxPageSize {
+		return nil, errors.Errorf("page %s is too big", url)
+	}
+
+	content := string(body)
+	if strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return ExtractHTML(content, url), nil
+	}
+	return Extract(content, url), nil
+}
+
+//findAll returns host and port of all matches. Matches which are part of a longer
+//dotted or numeric sequence (i.e. 1.2.3.4.5:80) are skipped.
+func findAll(re *regexp.Regexp, content string) []match {
+	result := make([]match, 0)
+	for _, idx := range re.FindAllStringSubmatchIndex(content, -1) {
+		hostStart := idx[2]
+		if hostStart > 0 && strings.ContainsAny(content[hostStart-1:hostStart], ".0123456789") {
+			continue
+		}
+		m := match{
+			host:     content[idx[2]:idx[3]],
+			port:     content[idx[4]:idx[5]],
+			position: hostStart,
+		}
+		//schemes are short, so only the text right before the host is searched
+		prefixStart := hostStart - 16
+		if prefixStart < 0 {
+			prefixStart = 0
+		}
+		if scheme := schemeRegex.FindStringSubmatch(content[prefixStart:hostStart]); scheme != nil {
+			m.scheme = scheme[1]
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+func parseIPv6(host, port string) (*proxy.Proxy, error) {
+	px, err := proxy.Parse("[" + host + "]:" + port)
+	if err != nil {
+		return nil, err
+	}
+	if px.IP.To4() != nil {
+		return nil, errors.New("not an ipv6 address")
+	}
+	return px, nil
+}
+
+func looksLikeHTML(content string) bool {
+	lower := strings.ToLower(content)
+	return strings.Contains(lower, "<html") || strings.Contains(lower, "<body") ||
+		strings.Contains(lower, "<table") || strings.Contains(lower, "<td")
+}
+
+//htmlToText converts html to plain text. Table cells are separated by tabs, rows and
+//blocks by new lines, inline tags are removed.
+func htmlToText(content string) string {
+	content = invisibleRegex.ReplaceAllString(content, "")
+	content = removeHidden(content)
+	//whitespace (including new lines) is not significant in html
+	content = whitespaceRegex.ReplaceAllString(content, " ")
+	content = cellRegex.ReplaceAllString(content, "\t")
+	content = blockRegex.ReplaceAllString(content, "\n")
+	content = tagRegex.ReplaceAllString(content, "")
+	content = html.UnescapeString(content)
+	content = strings.Replace(content, "\u00a0", " ", -1)
+
+	//normalize whitespace inside the lines, keeping tabs as cell separators
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		cells := strings.Split(line, "\t")
+		for j, cell := range cells {
+			cells[j] = strings.TrimSpace(cell)
+		}
+		lines[i] = strings.Join(cells, "\t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+//removeHidden removes hidden elements. Only elements closed with the same tag are removed
+//(regexp can't match back references)
+func removeHidden(content string) string {
+	return hiddenRegex.ReplaceAllStringFunc(content, func(element string) string {
+		sub := hiddenRegex.FindStringSubmatch(element)
+		if strings.EqualFold(sub[1], sub[2]) {
+			return ""
+		}
+		return element
+	})
+}
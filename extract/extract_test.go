@@ -0,0 +1,77 @@
+package extract
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alekc/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func proxyStrings(proxies []*proxy.Proxy) []string {
+	result := make([]string, 0, len(proxies))
+	for _, px := range proxies {
+		result = append(result, px.ToString())
+	}
+	return result
+}
+
+func fixtureServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, err := ioutil.ReadFile(filepath.Join("testdata", filepath.Base(r.URL.Path)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if filepath.Ext(r.URL.Path) == ".html" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "text/plain")
+		}
+		_, _ = w.Write(content)
+	}))
+}
+
+func TestFetch(t *testing.T) {
+	server := fixtureServer(t)
+	defer server.Close()
+
+	tests := map[string][]string{
+		"table.html":      {"1.2.3.4:8080", "5.6.7.8:3128", "[2001:db8::1]:1080"},
+		"obfuscated.html": {"10.0.0.1:8000", "10.0.0.2:8001", "10.0.0.3:8002", "[2001:db8::2]:8003", "proxy.example.com:8004"},
+		"paste.txt":       {"1.1.1.1:80", "2.2.2.2:3128", "3.3.3.3:1080", "[2001:db8::3]:443"},
+	}
+	for page, expected := range tests {
+		proxies, err := Fetch(server.Client(), server.URL+"/"+page)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, proxyStrings(proxies), "unexpected proxies in %s", page)
+		for _, px := range proxies {
+			assert.Equal(t, server.URL+"/"+page, px.Source, "source should be recorded")
+		}
+	}
+
+	_, err := Fetch(server.Client(), server.URL+"/missing.html")
+	assert.Error(t, err)
+}
+
+func TestExtractText(t *testing.T) {
+	proxies := ExtractText("11.2.3.45:80 and 1.2.3.4:8080, 1.2.3.4:8080", "manual")
+	assert.Equal(t, []string{"11.2.3.45:80", "1.2.3.4:8080"}, proxyStrings(proxies))
+	assert.Equal(t, "manual", proxies[0].Source)
+
+	proxies = ExtractText("1.2.3.4:8080 socks5://5.6.7.8:1080 http://1.2.3.4:8080 https://[2001:db8::1]:443 ftp://9.9.9.9:21", "")
+	assert.Equal(t, []string{"1.2.3.4:8080", "5.6.7.8:1080", "[2001:db8::1]:443", "9.9.9.9:21"}, proxyStrings(proxies))
+	assert.Equal(t, proxy.TypeHTTP, proxies[0].Type, "type should be taken from the later url")
+	assert.Equal(t, proxy.TypeSocks5, proxies[1].Type)
+	assert.Equal(t, proxy.TypeHTTPS, proxies[2].Type)
+	assert.Equal(t, proxy.TypeUnknown, proxies[3].Type, "unknown schemes are ignored")
+
+	proxies = ExtractText("1.2.3.4:8080 http://1.2.3.4:8080 socks5://1.2.3.4:8080 1.2.3.4:8080 socks5://1.2.3.4:8080", "")
+	if assert.Len(t, proxies, 2, "the same address with different schemes should be kept") {
+		assert.Equal(t, proxy.TypeHTTP, proxies[0].Type)
+		assert.Equal(t, proxy.TypeSocks5, proxies[1].Type)
+	}
+}
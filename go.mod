@@ -7,8 +7,10 @@ require (
 	github.com/alecthomas/units v0.0.0-20151022065526-2efee857e7cf // indirect
 	github.com/alekc/socks v0.0.0-20170517160848-d14f9ae68f10
 	github.com/mailru/easyjson v0.0.0-20190403194419-1ea4449da983
+	github.com/oschwald/maxminddb-golang v1.3.1
 	github.com/pkg/errors v0.8.1
 	github.com/sirupsen/logrus v1.4.1
 	github.com/stretchr/testify v1.3.0
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
+	gopkg.in/yaml.v2 v2.2.2
 )
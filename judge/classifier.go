@@ -0,0 +1,93 @@
+package judge
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+//Known categories of proxy ips
+const (
+	//CategoryDatacenter - hosting or cloud provider network
+	CategoryDatacenter = "datacenter"
+	//CategoryTor - tor exit node
+	CategoryTor = "tor"
+	//CategoryVPN - commercial vpn network
+	CategoryVPN = "vpn"
+)
+
+//Classifier tags ips with categories (i.e. datacenter, tor, vpn) of the range lists containing them.
+//Every category is a Ranges, so lists are loaded from files or urls (cidrs, ips or tor exit lists,
+//see ParseRanges) and refreshed without blocking lookups. Categories have to be added before the
+//classifier is used.
+type Classifier struct {
+	categories []string
+	ranges     map[string]*Ranges
+}
+
+//NewClassifier creates classifier without categories
+func NewClassifier() *Classifier {
+	return &Classifier{ranges: make(map[string]*Ranges)}
+}
+
+//Add adds sources of the category, returns ranges of the category (to set i.e. Interval)
+func (c *Classifier) Add(category string, sources ...string) *Ranges {
+	ranges, ok := c.ranges[category]
+	if !ok {
+		ranges = NewRanges(nil)
+		c.ranges[category] = ranges
+		c.categories = append(c.categories, category)
+	}
+	ranges.Sources = append(ranges.Sources, sources...)
+	return ranges
+}
+
+//Categories returns names of the categories in the order they were added
+func (c *Classifier) Categories() []string {
+	return append([]string(nil), c.categories...)
+}
+
+//SetLogger sets the logger used to report loading problems
+func (c *Classifier) SetLogger(log *logrus.Logger) {
+	for _, ranges := range c.ranges {
+		ranges.SetLogger(log)
+	}
+}
+
+//Load loads all categories. Categories which fail to load keep their current ranges,
+//the first error is returned.
+func (c *Classifier) Load(ctx context.Context) error {
+	var result error
+	for _, category := range c.categories {
+		if err := c.ranges[category].Load(ctx); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+//Run refreshes all categories (see Ranges.Run) until the context is cancelled
+func (c *Classifier) Run(ctx context.Context) {
+	wg := sync.WaitGroup{}
+	for _, category := range c.categories {
+		wg.Add(1)
+		go func(ranges *Ranges) {
+			defer wg.Done()
+			ranges.Run(ctx)
+		}(c.ranges[category])
+	}
+	wg.Wait()
+}
+
+//Classify returns categories containing the ip, nil if there are none
+func (c *Classifier) Classify(ip net.IP) []string {
+	var result []string
+	for _, category := range c.categories {
+		if c.ranges[category].Contains(ip) {
+			result = append(result, category)
+		}
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package judge
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRanges_TorExitList(t *testing.T) {
+	networks, errs := ParseRanges(strings.NewReader(`
+ExitNode 0011BD2485AD45D984EC4159C88FC066E5E3300E
+Published 2019-05-01 10:09:25
+LastStatus 2019-05-01 11:03:03
+ExitAddress 162.247.74.201 2019-05-01 11:10:53
+ExitAddress 2001:db8::5 2019-05-01 11:10:53
+ExitAddress
+5.6.7.8
+`))
+	assert.Len(t, errs, 1)
+	set := NewRangeSet(networks)
+	assert.Equal(t, 3, set.Len())
+	assert.True(t, set.Contains(net.ParseIP("162.247.74.201")))
+	assert.False(t, set.Contains(net.ParseIP("162.247.74.202")))
+	assert.True(t, set.Contains(net.ParseIP("2001:db8::5")))
+	assert.True(t, set.Contains(net.ParseIP("5.6.7.8")))
+}
+
+func TestClassifier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "classifier")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+	dc := write("dc.txt", "5.6.0.0/16\n")
+	vpn := write("vpn.txt", "5.6.7.0/24\n")
+	tor := write("tor.txt", "ExitAddress 9.9.9.9 2019-05-01 11:10:53\n")
+
+	classifier := NewClassifier()
+	classifier.Add(CategoryDatacenter, dc)
+	classifier.Add(CategoryVPN, vpn)
+	classifier.Add(CategoryTor, tor)
+	assert.Equal(t, []string{CategoryDatacenter, CategoryVPN, CategoryTor}, classifier.Categories())
+	assert.Nil(t, classifier.Classify(net.ParseIP("5.6.7.8")), "nothing is loaded before Load")
+	assert.NoError(t, classifier.Load(context.Background()))
+
+	assert.Equal(t, []string{CategoryDatacenter, CategoryVPN}, classifier.Classify(net.ParseIP("5.6.7.8")))
+	assert.Equal(t, []string{CategoryDatacenter}, classifier.Classify(net.ParseIP("5.6.8.1")))
+	assert.Equal(t, []string{CategoryTor}, classifier.Classify(net.ParseIP("9.9.9.9")))
+	assert.Nil(t, classifier.Classify(net.ParseIP("1.2.3.4")))
+
+	//failed category keeps its ranges, the others are refreshed
+	write("dc.txt", "10.0.0.0/8\n")
+	assert.NoError(t, os.Remove(tor))
+	assert.Error(t, classifier.Load(context.Background()))
+	assert.Equal(t, []string{CategoryDatacenter}, classifier.Classify(net.ParseIP("10.1.1.1")))
+	assert.Equal(t, []string{CategoryTor}, classifier.Classify(net.ParseIP("9.9.9.9")))
+}
+
+func TestJudge_AnalyzeCategories(t *testing.T) {
+	classifier := NewClassifier()
+	ranges := classifier.Add(CategoryDatacenter)
+	networks, _ := ParseNetworks([]string{"5.6.7.0/24"})
+	ranges.set.Store(NewRangeSet(networks))
+
+	j := newTestJudge(nil)
+	j.Classifier = classifier
+	result := j.Analyze(newJudgeRequest("5.6.7.8:4000", "1.2.3.4", nil))
+	assert.Equal(t, []string{CategoryDatacenter}, result.Categories)
+	encoded, _ := result.MarshalJSON()
+	assert.Contains(t, string(encoded), `"categories":["datacenter"]`)
+
+	result = j.Analyze(newJudgeRequest("9.9.9.9:4000", "1.2.3.4", nil))
+	assert.Nil(t, result.Categories)
+	encoded, _ = result.MarshalJSON()
+	assert.NotContains(t, string(encoded), "categories")
+}
@@ -1,16 +1,17 @@
 package judge
 
 import (
-	"net"
 	"strings"
 )
 
-var cfRanges []*net.IPNet
+//CloudflareRangeURLs are the lists of cloudflare networks published by cloudflare
+var CloudflareRangeURLs = []string{
+	"https://www.cloudflare.com/ips-v4",
+	"https://www.cloudflare.com/ips-v6",
+}
 
-// load cloudflare network ranges
-func loadCfRanges() {
-	//todo : add dynamic loading from https://www.cloudflare.com/ips-v4
-	body := `
+//embedded cloudflare network ranges, used until (or if) the ranges can't be loaded
+const defaultCfRanges = `
 173.245.48.0/20
 103.21.244.0/22
 103.22.200.0/22
@@ -33,24 +34,8 @@ func loadCfRanges() {
 2a06:98c0::/29
 2c0f:f248::/32`
 
-	ipRanges := strings.Split(body, "\n")
-	for _, cidr := range ipRanges {
-		_, ipNet, err := net.ParseCIDR(cidr)
-		//skip on error
-		if err != nil {
-			continue
-		}
-		cfRanges = append(cfRanges, ipNet)
-	}
-}
-
-// Checks if ip belongs to a cloudflare network.
-func ipBelongsToCfNetwork(ip net.IP) bool {
-	for _, cidr := range cfRanges {
-		belongs := cidr.Contains(ip)
-		if belongs {
-			return true
-		}
-	}
-	return false
+//DefaultCloudflareRanges returns set of the embedded cloudflare networks
+func DefaultCloudflareRanges() *RangeSet {
+	networks, _ := ParseRanges(strings.NewReader(defaultCfRanges))
+	return NewRangeSet(networks)
 }
@@ -0,0 +1,190 @@
+package judge
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+//Edge describes an edge network (cdn, cloud load balancer, reverse proxy) in front of the judge
+type Edge interface {
+	Name() string
+	//Trusted checks if the ip belongs to the edge servers
+	Trusted(ip net.IP) bool
+	//ClientIPHeader returns the header with the address of the client connected to the edge,
+	//empty if the edge only appends to X-Forwarded-For
+	ClientIPHeader() string
+	//CountryHeader returns the header with the country of the client, empty if not supported
+	CountryHeader() string
+	//IgnoredHeaders returns headers added by the edge, they are not evidence of a proxy
+	IgnoredHeaders() []string
+}
+
+//rangeLoader is implemented by edges loading their networks from files or urls (see Ranges)
+type rangeLoader interface {
+	Load(ctx context.Context) error
+	Run(ctx context.Context)
+}
+
+//GenericEdge is a configurable Edge. Networks are loaded by Ranges, edge without Ranges trusts no ip.
+type GenericEdge struct {
+	Ranges       *Ranges
+	EdgeName     string
+	ClientHeader string
+	Country      string
+	Ignored      []string
+}
+
+var _ Edge = (*GenericEdge)(nil)
+
+//NewGenericEdge creates an edge without networks, headers have to be configured
+func NewGenericEdge(name string) *GenericEdge {
+	return &GenericEdge{
+		Ranges:   NewRanges(nil),
+		EdgeName: name,
+		Ignored:  make([]string, 0),
+	}
+}
+
+//NewCloudflareEdge creates Cloudflare edge using given networks (see DefaultCloudflareRanges)
+func NewCloudflareEdge(ranges *Ranges) *GenericEdge {
+	return &GenericEdge{
+		Ranges:       ranges,
+		EdgeName:     "cloudflare",
+		ClientHeader: "Cf-Connecting-Ip",
+		Country:      "Cf-Ipcountry",
+		Ignored:      []string{"Cf-Connecting-Ip", "Cf-Ipcountry", "Cf-Ray", "Cf-Visitor", "Cf-Ew-Via", "Cf-Worker", "Cdn-Loop"},
+	}
+}
+
+//Name implements Edge
+func (e *GenericEdge) Name() string {
+	return e.EdgeName
+}
+
+//Trusted implements Edge
+func (e *GenericEdge) Trusted(ip net.IP) bool {
+	return e.Ranges != nil && e.Ranges.Contains(ip)
+}
+
+//Load loads the networks of the edge (see Ranges.Load)
+func (e *GenericEdge) Load(ctx context.Context) error {
+	if e.Ranges == nil {
+		return nil
+	}
+	return e.Ranges.Load(ctx)
+}
+
+//Run refreshes the networks of the edge until the context is cancelled (see Ranges.Run)
+func (e *GenericEdge) Run(ctx context.Context) {
+	if e.Ranges != nil {
+		e.Ranges.Run(ctx)
+	}
+}
+
+//SetLogger sets the logger used to report loading problems
+func (e *GenericEdge) SetLogger(log *logrus.Logger) {
+	if e.Ranges != nil {
+		e.Ranges.SetLogger(log)
+	}
+}
+
+//ClientIPHeader implements Edge
+func (e *GenericEdge) ClientIPHeader() string {
+	return e.ClientHeader
+}
+
+//CountryHeader implements Edge
+func (e *GenericEdge) CountryHeader() string {
+	return e.Country
+}
+
+//IgnoredHeaders implements Edge
+func (e *GenericEdge) IgnoredHeaders() []string {
+	return e.Ignored
+}
+
+//edgePresets contains known edges. Networks of edges other than cloudflare have to be configured
+//(edge Ranges or trusted gateways of the judge).
+var edgePresets = map[string]func() *GenericEdge{
+	"cloudflare": func() *GenericEdge {
+		return NewCloudflareEdge(NewRanges(DefaultCloudflareRanges()))
+	},
+	"nginx": func() *GenericEdge {
+		edge := NewGenericEdge("nginx")
+		edge.ClientHeader = "X-Real-Ip"
+		edge.Ignored = []string{"X-Real-Ip", "X-Forwarded-Proto", "X-Forwarded-Host"}
+		return edge
+	},
+	"alb": func() *GenericEdge {
+		edge := NewGenericEdge("alb")
+		edge.Ignored = []string{"X-Amzn-Trace-Id", "X-Forwarded-Proto", "X-Forwarded-Port"}
+		return edge
+	},
+	"fastly": func() *GenericEdge {
+		edge := NewGenericEdge("fastly")
+		edge.ClientHeader = "Fastly-Client-Ip"
+		edge.Ignored = []string{"Fastly-Client-Ip", "Fastly-Ff", "Fastly-Ssl", "Fastly-Orig-Accept-Encoding", "Cdn-Loop", "X-Timer", "X-Varnish"}
+		return edge
+	},
+	"akamai": func() *GenericEdge {
+		edge := NewGenericEdge("akamai")
+		edge.ClientHeader = "True-Client-Ip"
+		edge.Ignored = []string{"True-Client-Ip", "Akamai-Origin-Hop", "Akamai-Cache-Status", "X-Akamai-Config-Log-Detail"}
+		return edge
+	},
+	"generic": func() *GenericEdge {
+		return NewGenericEdge("generic")
+	},
+}
+
+//NewEdge creates a preconfigured edge by its name (see EdgeNames)
+func NewEdge(name string) (*GenericEdge, error) {
+	factory, ok := edgePresets[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown edge %s", name)
+	}
+	return factory(), nil
+}
+
+//EdgeNames returns names of all known edges
+func EdgeNames() []string {
+	names := make([]string, 0, len(edgePresets))
+	for name := range edgePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//edge returns the edge in front of the judge, Cloudflare if only CloudFlareSupport is set
+func (j *Judge) edge() Edge {
+	if j.Edge != nil {
+		return j.Edge
+	}
+	if !j.CloudFlareSupport {
+		return nil
+	}
+	//built once, again only if CloudflareRanges has been replaced
+	if edge, ok := j.cloudflareEdge.Load().(*GenericEdge); ok && edge.Ranges == j.CloudflareRanges {
+		return edge
+	}
+	edge := NewCloudflareEdge(j.CloudflareRanges)
+	j.cloudflareEdge.Store(edge)
+	return edge
+}
+
+//removeEdgeHeaders removes headers added by the edge
+func removeEdgeHeaders(headers http.Header, edge Edge) {
+	if edge == nil {
+		return
+	}
+	for _, name := range edge.IgnoredHeaders() {
+		headers.Del(name)
+	}
+}
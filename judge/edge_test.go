@@ -0,0 +1,136 @@
+package judge
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/alekc/proxy"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEdge(t *testing.T) {
+	assert.Equal(t, []string{"akamai", "alb", "cloudflare", "fastly", "generic", "nginx"}, EdgeNames())
+	for _, name := range EdgeNames() {
+		edge, err := NewEdge(name)
+		if assert.NoError(t, err) {
+			assert.Equal(t, name, edge.Name())
+		}
+	}
+	edge, _ := NewEdge("Cloudflare")
+	assert.True(t, edge.Trusted(net.ParseIP("173.245.48.1")))
+	assert.Equal(t, "Cf-Connecting-Ip", edge.ClientIPHeader())
+
+	_, err := NewEdge("unknown")
+	assert.Error(t, err)
+
+	//edge without ranges trusts nothing and can be served
+	literal := &GenericEdge{EdgeName: "literal"}
+	assert.False(t, literal.Trusted(net.ParseIP("1.2.3.4")))
+	assert.NoError(t, literal.Load(context.Background()))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	literal.Run(ctx)
+	literal.SetLogger(logrus.New())
+}
+
+func TestJudge_CloudflareEdge(t *testing.T) {
+	j := newTestJudge(nil)
+	j.CloudFlareSupport = true
+	assert.True(t, j.edge() == j.edge(), "cloudflare edge should be built once")
+
+	j.CloudflareRanges = NewRanges(nil)
+	edge, ok := j.edge().(*GenericEdge)
+	if assert.True(t, ok) {
+		assert.True(t, edge.Ranges == j.CloudflareRanges, "replaced ranges should be used")
+	}
+}
+
+func TestJudge_AnalyzeBehindEdge(t *testing.T) {
+	nginx, _ := NewEdge("nginx")
+	nginx.Ranges = NewRanges(NewRangeSet([]*net.IPNet{{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)}}))
+
+	alb, _ := NewEdge("alb")
+	alb.Ranges = NewRanges(NewRangeSet([]*net.IPNet{{IP: net.IP{172, 16, 0, 0}, Mask: net.CIDRMask(12, 32)}}))
+
+	geo := NewGenericEdge("geo")
+	geo.ClientHeader = "X-Client-Address"
+	geo.Country = "X-Country"
+	geo.Ignored = []string{"X-Client-Address", "X-Country"}
+
+	tests := []struct {
+		name       string
+		edge       Edge
+		gateways   []string
+		remoteAddr string
+		headers    map[string]string
+		anonymity  proxy.AnonymityLevel
+		remoteIP   string
+		country    string
+	}{
+		{
+			name:       "nginx",
+			edge:       nginx,
+			remoteAddr: "10.0.0.1:4000",
+			headers:    map[string]string{"X-Real-Ip": "5.6.7.8", "X-Forwarded-For": "5.6.7.8", "X-Forwarded-Proto": "http"},
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "5.6.7.8",
+		},
+		{
+			name:       "client header from untrusted peer is ignored",
+			edge:       nginx,
+			remoteAddr: "5.6.7.8:4000",
+			headers:    map[string]string{"X-Real-Ip": "9.9.9.9"},
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "5.6.7.8",
+		},
+		{
+			name:       "edge headers from untrusted peer are kept",
+			edge:       nginx,
+			remoteAddr: "5.6.7.8:4000",
+			headers:    map[string]string{"X-Real-Ip": "1.2.3.4"},
+			anonymity:  proxy.AnonHiddenProxy,
+			remoteIP:   "5.6.7.8",
+		},
+		{
+			name:       "alb",
+			edge:       alb,
+			remoteAddr: "172.16.5.5:4000",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 5.6.7.8", "X-Amzn-Trace-Id": "Root=1-5759e988"},
+			anonymity:  proxy.AnonTransparent,
+			remoteIP:   "5.6.7.8",
+		},
+		{
+			name:       "generic edge trusted with gateways",
+			edge:       geo,
+			gateways:   []string{"192.0.2.0/24"},
+			remoteAddr: "192.0.2.10:4000",
+			headers:    map[string]string{"X-Client-Address": "[2001:db8::5]:3128", "X-Country": "NL"},
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "2001:db8::5",
+			country:    "NL",
+		},
+		{
+			name:       "edge takes precedence over cloudflare support",
+			edge:       NewGenericEdge("none"),
+			remoteAddr: "173.245.48.1:4000",
+			headers:    map[string]string{"Cf-Connecting-Ip": "5.6.7.8", "Cf-Ipcountry": "DE"},
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "173.245.48.1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			j := newTestJudge(nil)
+			j.CloudFlareSupport = true
+			j.Edge = test.edge
+			assert.NoError(t, j.SetTrustedGateways(test.gateways))
+
+			result := j.Analyze(newJudgeRequest(test.remoteAddr, "1.2.3.4", test.headers))
+			assert.Equal(t, test.anonymity, result.AnonType)
+			assert.Equal(t, test.remoteIP, result.RemoteIP.String())
+			assert.Equal(t, test.country, result.Country)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package judge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"regexp"
+	"strings"
+
+	"github.com/alekc/proxy"
+)
+
+//Fingerprint identifies proxy software by a header value
+type Fingerprint struct {
+	Software string `json:"software"`
+	//Header to match (Via is matched per entry)
+	Header string `json:"header"`
+	//Case insensitive regular expression. Version is taken from the "version" group if present.
+	Pattern string `json:"pattern"`
+
+	re *regexp.Regexp
+}
+
+//FingerprintTable is an ordered list of fingerprints, the first matching fingerprint wins
+//(specific patterns have to precede generic ones). Table is immutable and safe for concurrent use.
+type FingerprintTable struct {
+	fingerprints []Fingerprint
+}
+
+//DefaultFingerprints is the built in fingerprint table
+var DefaultFingerprints = []Fingerprint{
+	{Software: "squid", Header: "Via", Pattern: `squid/(?P<version>[0-9][\w.]*)`},
+	{Software: "squid", Header: "Via", Pattern: `squid`},
+	{Software: "squid", Header: "X-Squid-Error", Pattern: `.`},
+	{Software: "varnish", Header: "Via", Pattern: `varnish/(?P<version>[0-9][\w.]*)`},
+	{Software: "varnish", Header: "Via", Pattern: `varnish`},
+	{Software: "varnish", Header: "X-Varnish", Pattern: `.`},
+	{Software: "apache traffic server", Header: "Via", Pattern: `(?:ApacheTrafficServer|\bATS)/(?P<version>[0-9][\w.]*)`},
+	{Software: "apache traffic server", Header: "Via", Pattern: `ApacheTrafficServer`},
+	{Software: "bluecoat", Header: "X-Bluecoat-Via", Pattern: `.`},
+	{Software: "bluecoat", Header: "Via", Pattern: `blue ?coat|proxysg`},
+	{Software: "nginx", Header: "Via", Pattern: `nginx/(?P<version>[0-9][\w.]*)`},
+	{Software: "nginx", Header: "Via", Pattern: `nginx`},
+	{Software: "tinyproxy", Header: "Via", Pattern: `tinyproxy(?:/(?P<version>[0-9][\w.]*))?`},
+	{Software: "apache", Header: "Via", Pattern: `\bApache/(?P<version>[0-9][\w.]*)`},
+	{Software: "haproxy", Header: "Via", Pattern: `haproxy`},
+	{Software: "polipo", Header: "Via", Pattern: `polipo`},
+	{Software: "privoxy", Header: "Via", Pattern: `privoxy`},
+	{Software: "ccproxy", Header: "Via", Pattern: `ccproxy`},
+	{Software: "wingate", Header: "Via", Pattern: `wingate`},
+	{Software: "mikrotik", Header: "Via", Pattern: `mikrotik(?: ?http ?proxy)?`},
+	{Software: "zscaler", Header: "Via", Pattern: `zscaler`},
+	{Software: "cloudfront", Header: "Via", Pattern: `cloudfront`},
+	{Software: "heroku router", Header: "Via", Pattern: `\bvegur\b`},
+	{Software: "google frontend", Header: "Via", Pattern: `\bgoogle\b`},
+	{Software: "iwproxy", Header: "X-Iwproxy", Pattern: `.`},
+}
+
+//NewFingerprintTable validates and compiles the fingerprints
+func NewFingerprintTable(fingerprints []Fingerprint) (*FingerprintTable, error) {
+	table := &FingerprintTable{fingerprints: make([]Fingerprint, 0, len(fingerprints))}
+	for i, fp := range fingerprints {
+		if fp.Software == "" || fp.Header == "" || fp.Pattern == "" {
+			return nil, fmt.Errorf("fingerprint %d: software, header and pattern are required", i)
+		}
+		re, err := regexp.Compile("(?i)" + fp.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("fingerprint %d (%s): %s", i, fp.Software, err)
+		}
+		fp.Header = textproto.CanonicalMIMEHeaderKey(fp.Header)
+		fp.re = re
+		table.fingerprints = append(table.fingerprints, fp)
+	}
+	return table, nil
+}
+
+//DefaultFingerprintTable returns table of DefaultFingerprints
+func DefaultFingerprintTable() *FingerprintTable {
+	table, err := NewFingerprintTable(DefaultFingerprints)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+//LoadFingerprints reads json array of fingerprints from the file. If extend is set the loaded
+//fingerprints take precedence over DefaultFingerprints, otherwise they replace them.
+func LoadFingerprints(path string, extend bool) (*FingerprintTable, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fingerprints := make([]Fingerprint, 0)
+	if err := json.Unmarshal(content, &fingerprints); err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", path, err)
+	}
+	if extend {
+		fingerprints = append(fingerprints, DefaultFingerprints...)
+	}
+	return NewFingerprintTable(fingerprints)
+}
+
+//Len returns number of fingerprints
+func (t *FingerprintTable) Len() int {
+	return len(t.fingerprints)
+}
+
+//Identify returns software identified in the headers. Every Via entry and every value of other
+//headers is matched separately, so chained proxies are reported one by one.
+func (t *FingerprintTable) Identify(headers http.Header) []proxy.Software {
+	result := make([]proxy.Software, 0)
+	if t == nil {
+		return result
+	}
+	identify := func(header, value string) {
+		for _, fp := range t.fingerprints {
+			if fp.Header != header {
+				continue
+			}
+			if match := fp.re.FindStringSubmatch(value); match != nil {
+				result = append(result, proxy.Software{
+					Name:    fp.Software,
+					Version: matchedVersion(fp.re, match),
+					Header:  header,
+					Value:   value,
+				})
+				return
+			}
+		}
+	}
+
+	for _, entry := range splitVia(headers["Via"]) {
+		identify("Via", entry)
+	}
+	//other headers in the order of the table
+	seen := map[string]bool{"Via": true}
+	for _, fp := range t.fingerprints {
+		if seen[fp.Header] {
+			continue
+		}
+		seen[fp.Header] = true
+		for _, value := range headers[fp.Header] {
+			identify(fp.Header, strings.TrimSpace(value))
+		}
+	}
+	return result
+}
+
+//matchedVersion returns the "version" group of the match
+func matchedVersion(re *regexp.Regexp, match []string) string {
+	for i, name := range re.SubexpNames() {
+		if name == "version" {
+			return match[i]
+		}
+	}
+	return ""
+}
+
+//ParseVia parses Via header values (RFC 7230 section 5.7.1), oldest entry first
+func ParseVia(values []string) []proxy.ViaEntry {
+	entries := make([]proxy.ViaEntry, 0)
+	for _, raw := range splitVia(values) {
+		entry := proxy.ViaEntry{}
+		if i := strings.Index(raw, "("); i >= 0 {
+			entry.Comment = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(raw[i:], "("), ")"))
+			raw = raw[:i]
+		}
+		fields := strings.Fields(raw)
+		if len(fields) > 0 {
+			entry.Protocol = fields[0]
+		}
+		if len(fields) > 1 {
+			entry.ReceivedBy = fields[1]
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+//splitVia splits Via values to entries, commas inside of comments are ignored
+func splitVia(values []string) []string {
+	entries := make([]string, 0)
+	for _, value := range values {
+		depth := 0
+		start := 0
+		for i := 0; i < len(value); i++ {
+			switch value[i] {
+			case '\\':
+				i++
+			case '(':
+				depth++
+			case ')':
+				if depth > 0 {
+					depth--
+				}
+			case ',':
+				if depth == 0 {
+					entries = appendEntry(entries, value[start:i])
+					start = i + 1
+				}
+			}
+		}
+		entries = appendEntry(entries, value[start:])
+	}
+	return entries
+}
+
+func appendEntry(entries []string, entry string) []string {
+	if entry = strings.TrimSpace(entry); entry != "" {
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,131 @@
+package judge
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alekc/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVia(t *testing.T) {
+	entries := ParseVia([]string{
+		"1.0 fred, 1.1 p.example.net:3128 (squid/3.5.27)",
+		`HTTP/2.0 edge (comment, with comma (nested)), 1.1 last`,
+		" , ",
+	})
+	assert.Equal(t, []proxy.ViaEntry{
+		{Protocol: "1.0", ReceivedBy: "fred"},
+		{Protocol: "1.1", ReceivedBy: "p.example.net:3128", Comment: "squid/3.5.27"},
+		{Protocol: "HTTP/2.0", ReceivedBy: "edge", Comment: "comment, with comma (nested)"},
+		{Protocol: "1.1", ReceivedBy: "last"},
+	}, entries)
+}
+
+func TestFingerprintTable_Identify(t *testing.T) {
+	table := DefaultFingerprintTable()
+	tests := []struct {
+		header   string
+		value    string
+		software string
+		version  string
+	}{
+		{"Via", "1.1 proxy.example.com:3128 (squid/3.5.27)", "squid", "3.5.27"},
+		{"Via", "1.1 squid-cache", "squid", ""},
+		{"Via", "1.1 varnish (Varnish/6.0)", "varnish", "6.0"},
+		{"Via", "http/1.1 ats.example.com (ApacheTrafficServer/9.1.2 [cMsSf ])", "apache traffic server", "9.1.2"},
+		{"Via", "1.1 host (Apache/2.4.41 (Unix))", "apache", "2.4.41"},
+		{"Via", "1.1 cache (tinyproxy/1.10.0)", "tinyproxy", "1.10.0"},
+		{"Via", "1.1 nginx", "nginx", ""},
+		{"Via", "1.1 vegur", "heroku router", ""},
+		{"X-Bluecoat-Via", "ab12cd34ef56", "bluecoat", ""},
+		{"X-Varnish", "32770", "varnish", ""},
+	}
+	for _, test := range tests {
+		software := table.Identify(http.Header{test.header: {test.value}})
+		if assert.Len(t, software, 1, test.value) {
+			assert.Equal(t, test.software, software[0].Name, test.value)
+			assert.Equal(t, test.version, software[0].Version, test.value)
+			assert.Equal(t, test.header, software[0].Header)
+			assert.Equal(t, test.value, software[0].Value)
+		}
+	}
+
+	//chained proxies are identified one by one
+	software := table.Identify(http.Header{
+		"Via":       {"1.1 a (squid/4.10), 1.1 unknown-proxy", "1.1 b (Varnish/6.0)"},
+		"X-Varnish": {"1234"},
+	})
+	if assert.Len(t, software, 3) {
+		assert.Equal(t, "squid", software[0].Name)
+		assert.Equal(t, "varnish", software[1].Name)
+		assert.Equal(t, "X-Varnish", software[2].Header)
+	}
+	assert.Empty(t, table.Identify(http.Header{"Via": {"1.1 unknown"}}))
+}
+
+func TestLoadFingerprints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "judge")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fingerprints.json")
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`[
+		{"software": "corporate proxy", "header": "via", "pattern": "squid/4\\.10-corp"},
+		{"software": "gateway", "header": "x-gateway-id", "pattern": "gw-(?P<version>\\d+)"}
+	]`), 0600))
+
+	table, err := LoadFingerprints(path, false)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, table.Len())
+		software := table.Identify(http.Header{"X-Gateway-Id": {"gw-42"}})
+		if assert.Len(t, software, 1) {
+			assert.Equal(t, "gateway", software[0].Name)
+			assert.Equal(t, "42", software[0].Version)
+		}
+		assert.Empty(t, table.Identify(http.Header{"Via": {"1.1 a (squid/4.9)"}}))
+	}
+
+	//loaded fingerprints take precedence over the defaults
+	table, err = LoadFingerprints(path, true)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2+len(DefaultFingerprints), table.Len())
+		software := table.Identify(http.Header{"Via": {"1.1 a (squid/4.10-corp)", "1.1 b (squid/4.9)"}})
+		if assert.Len(t, software, 2) {
+			assert.Equal(t, "corporate proxy", software[0].Name)
+			assert.Equal(t, "squid", software[1].Name)
+		}
+	}
+
+	invalid := []string{
+		`{"software": "x"}`,
+		`[{"software": "x", "header": "Via"}]`,
+		`[{"software": "x", "header": "Via", "pattern": "(unclosed"}]`,
+	}
+	for _, content := range invalid {
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+		_, err := LoadFingerprints(path, true)
+		assert.Error(t, err, content)
+	}
+	_, err = LoadFingerprints(filepath.Join(dir, "missing.json"), true)
+	assert.Error(t, err)
+}
+
+func TestJudge_AnalyzeProxySoftware(t *testing.T) {
+	j := newTestJudge(nil)
+	result := j.Analyze(newJudgeRequest("5.6.7.8:4000", "1.2.3.4", map[string]string{
+		"Via": "1.1 cache.example.com (squid/3.5.27)",
+	}))
+	assert.Equal(t, []proxy.ViaEntry{{Protocol: "1.1", ReceivedBy: "cache.example.com", Comment: "squid/3.5.27"}}, result.Via)
+	if assert.Len(t, result.ProxySoftware, 1) {
+		assert.Equal(t, "squid", result.ProxySoftware[0].Name)
+		assert.Equal(t, "3.5.27", result.ProxySoftware[0].Version)
+	}
+
+	result = j.Analyze(newJudgeRequest("5.6.7.8:4000", "1.2.3.4", nil))
+	assert.Nil(t, result.Via)
+	assert.Nil(t, result.ProxySoftware)
+}
@@ -0,0 +1,277 @@
+package judge
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/alekc/proxy"
+)
+
+//private, loopback, link local and shared (carrier grade nat) networks
+var privateNetworks = func() *RangeSet {
+	networks, _ := ParseRanges(strings.NewReader(`
+10.0.0.0/8
+172.16.0.0/12
+192.168.0.0/16
+100.64.0.0/10
+127.0.0.0/8
+169.254.0.0/16
+::1/128
+fc00::/7
+fe80::/10`))
+	return NewRangeSet(networks)
+}()
+
+//hop is a single entry of the forwarding chain (X-Forwarded-For or Forwarded header)
+type hop struct {
+	//original text of the entry, used to rebuild the header
+	raw string
+	//address of the hop as written in the header (for parameter of the Forwarded element)
+	value string
+	//nil for unknown or obfuscated identifiers (RFC 7239 "unknown", "_hidden")
+	ip net.IP
+}
+
+//ParseNetworks converts ips and cidrs (i.e. "10.0.0.1", "10.0.0.0/8", "2001:db8::/32") to networks.
+//A single ip is converted to a /32 (/128 for ipv6) network.
+func ParseNetworks(list []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(list))
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.Contains(item, "/") {
+			_, network, err := net.ParseCIDR(item)
+			if err != nil {
+				return nil, err
+			}
+			networks = append(networks, network)
+			continue
+		}
+		ip := net.ParseIP(stripPort(item))
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip address %s", item)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(8*len(ip), 8*len(ip))})
+	}
+	return networks, nil
+}
+
+//parseXForwardedFor splits X-Forwarded-For values to hops, oldest (client) first
+func parseXForwardedFor(values []string) []hop {
+	hops := make([]hop, 0)
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			hops = append(hops, hop{raw: entry, value: entry, ip: net.ParseIP(stripPort(entry))})
+		}
+	}
+	return hops
+}
+
+//parseForwarded splits RFC 7239 Forwarded values (for=192.0.2.43;proto=http, for="[2001:db8::1]:4711")
+//to hops, oldest first. Elements without the for parameter are kept as unknown hops.
+func parseForwarded(values []string) []hop {
+	hops := make([]hop, 0)
+	for _, value := range values {
+		for _, element := range splitQuoted(value, ',') {
+			element = strings.TrimSpace(element)
+			if element == "" {
+				continue
+			}
+			h := hop{raw: element}
+			for _, pair := range splitQuoted(element, ';') {
+				parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+				if len(parts) == 2 && strings.EqualFold(strings.TrimSpace(parts[0]), "for") {
+					h.value = strings.Trim(strings.TrimSpace(parts[1]), `"`)
+					h.ip = net.ParseIP(stripPort(h.value))
+				}
+			}
+			hops = append(hops, h)
+		}
+	}
+	return hops
+}
+
+//splitQuoted splits the string on the separator, ignoring separators inside of quoted strings
+func splitQuoted(s string, separator byte) []string {
+	result := make([]string, 0)
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == '\\' && quoted:
+			i++
+		case s[i] == separator && !quoted:
+			result = append(result, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(result, s[start:])
+}
+
+//stripPort removes the port (and brackets) from "1.2.3.4:80", "[2001:db8::1]:80" and "[2001:db8::1]".
+//Bare ipv6 addresses are returned unchanged.
+func stripPort(s string) string {
+	if strings.HasPrefix(s, "[") {
+		if end := strings.Index(s, "]"); end > 0 {
+			return s[1:end]
+		}
+		return s
+	}
+	if strings.Count(s, ":") == 1 {
+		return s[:strings.Index(s, ":")]
+	}
+	return s
+}
+
+//trust decides which hops of the forwarding chain are trusted
+type trust struct {
+	gateways *RangeSet
+	edge     Edge
+}
+
+//trust returns trusted gateways and the edge
+func (j *Judge) trust() *trust {
+	return &trust{gateways: j.trustedGateways(), edge: j.edge()}
+}
+
+//SetTrustedGateways validates the gateways (ips or cidrs) and sets TrustedGatewaysIps.
+//Current gateways are kept if any entry is invalid.
+func (j *Judge) SetTrustedGateways(gateways []string) error {
+	if err := j.loadTrustedGateways(gateways); err != nil {
+		return err
+	}
+	j.TrustedGatewaysIps = gateways
+	return nil
+}
+
+//loadTrustedGateways parses the gateways and caches the result
+func (j *Judge) loadTrustedGateways(gateways []string) error {
+	networks, err := ParseNetworks(gateways)
+	if err != nil {
+		return fmt.Errorf("invalid trusted gateway: %s", err)
+	}
+	j.gateways.Store(NewRangeSet(networks))
+	return nil
+}
+
+//noGateways is used until the gateways are loaded
+var noGateways = NewRangeSet(nil)
+
+//trustedGateways returns the gateways loaded by SetTrustedGateways or when the server started
+func (j *Judge) trustedGateways() *RangeSet {
+	if set, ok := j.gateways.Load().(*RangeSet); ok {
+		return set
+	}
+	return noGateways
+}
+
+//contains checks if the ip is a trusted gateway or an edge server
+func (t *trust) contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	return t.gateways.Contains(ip) || (t.edge != nil && t.edge.Trusted(ip))
+}
+
+//isTrustedHop checks if the hop at the position of the chain (ending with the peer) is trusted.
+//Edge servers are reported separately.
+func (j *Judge) isTrustedHop(chain []hop, i int, trusted *trust) bool {
+	return len(chain)-1-i < j.TrustedHops || trusted.gateways.Contains(chain[i].ip)
+}
+
+//clientIndex walks the chain from the right (the peer) skipping TrustedHops entries, trusted
+//gateways and edge servers. The first untrusted entry is the client of the judge (the proxy).
+//If the whole chain is trusted the leftmost entry is returned.
+func (j *Judge) clientIndex(chain []hop, trusted *trust) int {
+	for i := len(chain) - 1; i >= 0; i-- {
+		if j.isTrustedHop(chain, i, trusted) || trusted.contains(chain[i].ip) {
+			continue
+		}
+		return i
+	}
+	return 0
+}
+
+//normalizeForwarding removes entries added by trusted gateways (and the entry of the client itself)
+//from X-Forwarded-For and Forwarded headers, so only entries added by the proxy remain. Headers
+//without remaining entries are removed. Returns the client address derived from the chain and
+//the analysis of the original chains.
+func (j *Judge) normalizeForwarding(headers http.Header, peer net.IP, realIP string, trusted *trust) (net.IP, []proxy.ForwardingChain) {
+	peerHop := hop{ip: peer}
+	chains := make([]proxy.ForwardingChain, 0)
+
+	_, hasXFF := headers["X-Forwarded-For"]
+	xff := append(parseXForwardedFor(headers["X-Forwarded-For"]), peerHop)
+	if hasXFF {
+		chains = append(chains, j.analyzeChain("X-Forwarded-For", xff, realIP, trusted))
+	}
+	i := j.clientIndex(xff, trusted)
+	setChain(headers, "X-Forwarded-For", xff[:i])
+	client := xff[i].ip
+
+	//standardized header takes precedence
+	if values, ok := headers["Forwarded"]; ok {
+		forwarded := append(parseForwarded(values), peerHop)
+		chains = append(chains, j.analyzeChain("Forwarded", forwarded, realIP, trusted))
+		i = j.clientIndex(forwarded, trusted)
+		setChain(headers, "Forwarded", forwarded[:i])
+		client = forwarded[i].ip
+	}
+	if client == nil {
+		return peer, chains
+	}
+	return client, chains
+}
+
+//analyzeChain describes the hops of the chain (the last hop is the peer, it isn't reported)
+func (j *Judge) analyzeChain(header string, chain []hop, realIP string, trusted *trust) proxy.ForwardingChain {
+	realAddress := net.ParseIP(realIP)
+	result := proxy.ForwardingChain{
+		Header: header,
+		Hops:   make([]proxy.ForwardingHop, 0, len(chain)-1),
+	}
+	for i, h := range chain[:len(chain)-1] {
+		fh := proxy.ForwardingHop{
+			Value:   h.value,
+			IP:      h.ip,
+			Trusted: j.isTrustedHop(chain, i, trusted),
+			Edge:    h.ip != nil && trusted.edge != nil && trusted.edge.Trusted(h.ip),
+			Private: privateNetworks.Contains(h.ip),
+			RealIP:  h.ip != nil && h.ip.Equal(realAddress),
+		}
+		if !fh.Trusted && !fh.Edge {
+			result.UntrustedHops++
+			result.PrivateLeak = result.PrivateLeak || fh.Private
+		}
+		result.ContainsRealIP = result.ContainsRealIP || fh.RealIP
+		result.Hops = append(result.Hops, fh)
+	}
+	result.HopCount = len(result.Hops)
+	return result
+}
+
+//setChain replaces the header with given hops, empty chain removes the header
+func setChain(headers http.Header, name string, chain []hop) {
+	if len(chain) == 0 {
+		headers.Del(name)
+		return
+	}
+	raw := make([]string, len(chain))
+	for i, h := range chain {
+		raw[i] = h.raw
+	}
+	headers.Set(name, strings.Join(raw, ", "))
+}
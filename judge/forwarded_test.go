@@ -0,0 +1,121 @@
+package judge
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNetworks(t *testing.T) {
+	networks, err := ParseNetworks([]string{"10.0.0.1", " 192.168.0.0/16", "2001:db8::1", "2001:db8::/32", ""})
+	if assert.NoError(t, err) && assert.Len(t, networks, 4) {
+		assert.Equal(t, "10.0.0.1/32", networks[0].String())
+		assert.Equal(t, "192.168.0.0/16", networks[1].String())
+		assert.Equal(t, "2001:db8::1/128", networks[2].String())
+		assert.Equal(t, "2001:db8::/32", networks[3].String())
+	}
+	for _, invalid := range []string{"10.0.0.300", "10.0.0.0/33", "gateway"} {
+		_, err := ParseNetworks([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseXForwardedFor(t *testing.T) {
+	hops := parseXForwardedFor([]string{"1.2.3.4:80, [2001:db8::1]:443", " 2001:db8::2 ,unknown", ""})
+	if assert.Len(t, hops, 4) {
+		assert.Equal(t, net.ParseIP("1.2.3.4"), hops[0].ip)
+		assert.Equal(t, net.ParseIP("2001:db8::1"), hops[1].ip)
+		assert.Equal(t, net.ParseIP("2001:db8::2"), hops[2].ip)
+		assert.Nil(t, hops[3].ip)
+		assert.Equal(t, "unknown", hops[3].raw)
+	}
+}
+
+func TestParseForwarded(t *testing.T) {
+	hops := parseForwarded([]string{
+		`for=192.0.2.43:47011;proto=http;by=203.0.113.43, for="[2001:db8:cafe::17]:4711"`,
+		`for=_hidden, proto=https;host="a,b", for=unknown;by="quoted;value"`,
+	})
+	if assert.Len(t, hops, 5) {
+		assert.Equal(t, net.ParseIP("192.0.2.43"), hops[0].ip)
+		assert.Equal(t, "for=192.0.2.43:47011;proto=http;by=203.0.113.43", hops[0].raw)
+		assert.Equal(t, net.ParseIP("2001:db8:cafe::17"), hops[1].ip)
+		assert.Nil(t, hops[2].ip)
+		assert.Equal(t, `proto=https;host="a,b"`, hops[3].raw)
+		assert.Nil(t, hops[4].ip)
+	}
+}
+
+func TestJudge_ClientIndex(t *testing.T) {
+	j := newTestJudge(nil)
+	chain := parseXForwardedFor([]string{"1.1.1.1, 2.2.2.2, 10.0.0.1, 10.0.0.2"})
+	assert.Equal(t, 3, j.clientIndex(chain, j.trust()))
+
+	j.TrustedHops = 1
+	assert.Equal(t, 2, j.clientIndex(chain, j.trust()))
+
+	j.TrustedHops = 0
+	assert.NoError(t, j.SetTrustedGateways([]string{"10.0.0.0/24"}))
+	assert.Equal(t, 1, j.clientIndex(chain, j.trust()))
+	assert.Error(t, j.SetTrustedGateways([]string{"10.0.0.2", "invalid"}))
+	assert.Equal(t, []string{"10.0.0.0/24"}, j.TrustedGatewaysIps)
+	assert.Equal(t, 1, j.clientIndex(chain, j.trust()), "current gateways should be kept")
+
+	//edge servers are trusted as well
+	assert.NoError(t, j.SetTrustedGateways([]string{"10.0.0.2"}))
+	edge := NewGenericEdge("lb")
+	edge.Ranges = NewRanges(NewRangeSet([]*net.IPNet{{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(24, 32)}}))
+	j.Edge = edge
+	assert.Equal(t, 1, j.clientIndex(chain, j.trust()))
+
+	//whole chain trusted
+	j.TrustedHops = 10
+	assert.Equal(t, 0, j.clientIndex(chain, j.trust()))
+}
+
+func TestJudge_AnalyzeForwardingChain(t *testing.T) {
+	j := newTestJudge(nil)
+	j.CloudFlareSupport = true
+	assert.NoError(t, j.SetTrustedGateways([]string{"10.0.0.1"}))
+
+	result := j.Analyze(newJudgeRequest("173.245.48.1:4000", "1.2.3.4", map[string]string{
+		"X-Forwarded-For": "1.2.3.4, 192.168.1.5, 5.6.7.8:3128, 173.245.48.9",
+		"Forwarded":       "for=unknown, for=10.0.0.1",
+	}))
+	if !assert.Len(t, result.Forwarding, 2) {
+		return
+	}
+	xff := result.Forwarding[0]
+	assert.Equal(t, "X-Forwarded-For", xff.Header)
+	assert.Equal(t, 4, xff.HopCount)
+	assert.Equal(t, 3, xff.UntrustedHops)
+	assert.True(t, xff.ContainsRealIP)
+	assert.True(t, xff.PrivateLeak)
+	if assert.Len(t, xff.Hops, 4) {
+		assert.True(t, xff.Hops[0].RealIP)
+		assert.True(t, xff.Hops[1].Private)
+		assert.Equal(t, "5.6.7.8:3128", xff.Hops[2].Value)
+		assert.Equal(t, net.ParseIP("5.6.7.8"), xff.Hops[2].IP)
+		assert.False(t, xff.Hops[2].Edge || xff.Hops[2].Trusted || xff.Hops[2].Private)
+		assert.True(t, xff.Hops[3].Edge)
+	}
+
+	forwarded := result.Forwarding[1]
+	assert.Equal(t, "Forwarded", forwarded.Header)
+	assert.Equal(t, 2, forwarded.HopCount)
+	assert.Equal(t, 1, forwarded.UntrustedHops)
+	assert.False(t, forwarded.ContainsRealIP)
+	//private address of a trusted gateway isn't a leak
+	assert.False(t, forwarded.PrivateLeak)
+	if assert.Len(t, forwarded.Hops, 2) {
+		assert.Equal(t, "unknown", forwarded.Hops[0].Value)
+		assert.Nil(t, forwarded.Hops[0].IP)
+		assert.True(t, forwarded.Hops[1].Trusted)
+		assert.True(t, forwarded.Hops[1].Private)
+	}
+
+	//chains are reported only if the headers are present
+	result = j.Analyze(newJudgeRequest("5.6.7.8:4000", "1.2.3.4", nil))
+	assert.Nil(t, result.Forwarding)
+}
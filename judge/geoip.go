@@ -0,0 +1,393 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/sirupsen/logrus"
+)
+
+//GeoRecord contains location and network owner of an ip address
+type GeoRecord struct {
+	//Iso code of the country, i.e. "DE"
+	Country string
+	//English name of the city
+	City string
+	ASN  uint
+	//Organization owning the autonomous system
+	Org string
+}
+
+//merge fills empty fields of the record from the other one
+func (r *GeoRecord) merge(other GeoRecord) {
+	if r.Country == "" {
+		r.Country = other.Country
+	}
+	if r.City == "" {
+		r.City = other.City
+	}
+	if r.ASN == 0 {
+		r.ASN, r.Org = other.ASN, other.Org
+	}
+}
+
+//geoDatabase is a single loaded database file
+type geoDatabase interface {
+	lookup(ip net.IP) (GeoRecord, error)
+}
+
+//GeoIP enriches the judgement with location and owner of the proxy ip, using local MaxMind format
+//databases (GeoLite2/GeoIP2 City, Country or ASN) or csv range files. Files are reloaded when they change.
+//
+//Every line of the csv file contains a cidr or the first and the last ip of the range followed by
+//country, city, asn and organization (trailing fields can be omitted):
+//
+//	1.2.3.0/24,DE,Berlin,AS64500,Example Org
+//	1.2.4.0,1.2.5.255,US,,64501,Other Org
+//
+//Empty lines, comments (#) and lines not starting with an ip (i.e. a header) are skipped. Ranges can be
+//nested, the most specific one wins (i.e. a /24 overrides the enclosing /16). Partially overlapping ranges
+//are refused.
+type GeoIP struct {
+	//Database files (mmdb or csv). Every field is taken from the first database which has it,
+	//so i.e. a City and an ASN database can be combined.
+	Files []string
+	//Interval of checking the files for changes, used by Run
+	Interval time.Duration
+
+	databases atomic.Value
+	mu        sync.Mutex
+	modified  map[string]time.Time
+	logger    *logrus.Logger
+}
+
+//NewGeoIP creates GeoIP using given files. Files are read by Load.
+func NewGeoIP(files ...string) *GeoIP {
+	geo := &GeoIP{
+		Files:    files,
+		Interval: time.Minute,
+		modified: make(map[string]time.Time),
+	}
+	geo.databases.Store([]geoDatabase{})
+
+	//default logger (only errors are visible)
+	geo.logger = logrus.New()
+	geo.logger.Out = os.Stdout
+	geo.logger.SetLevel(logrus.ErrorLevel)
+	return geo
+}
+
+//SetLogger sets the logger used to report loading problems
+func (g *GeoIP) SetLogger(log *logrus.Logger) {
+	g.logger = log
+}
+
+//Load reads all files and replaces the current databases. If any file fails the current databases are kept.
+func (g *GeoIP) Load() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	databases := make([]geoDatabase, 0, len(g.Files))
+	modified := make(map[string]time.Time, len(g.Files))
+	for _, file := range g.Files {
+		info, err := os.Stat(file)
+		if err == nil {
+			modified[file] = info.ModTime()
+		}
+		db, err := openGeoDatabase(file)
+		if err != nil {
+			g.logger.
+				WithError(err).
+				WithField("file", file).
+				Error("couldn't load geoip database, keeping the current ones")
+			return err
+		}
+		databases = append(databases, db)
+	}
+	g.databases.Store(databases)
+	g.modified = modified
+	g.logger.WithField("databases", len(databases)).Debug("geoip databases loaded")
+	return nil
+}
+
+//changed checks if any of the files has been modified since the last Load
+func (g *GeoIP) changed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, file := range g.Files {
+		info, err := os.Stat(file)
+		if err == nil && !info.ModTime().Equal(g.modified[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+//Run reloads the databases when the files change, checking every Interval until the context is cancelled
+func (g *GeoIP) Run(ctx context.Context) {
+	if len(g.Files) == 0 || g.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(g.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if g.changed() {
+				_ = g.Load()
+			}
+		}
+	}
+}
+
+//Lookup returns the record of the ip. Empty record is returned for unknown ips.
+func (g *GeoIP) Lookup(ip net.IP) GeoRecord {
+	record := GeoRecord{}
+	if ip == nil {
+		return record
+	}
+	for _, db := range g.databases.Load().([]geoDatabase) {
+		found, err := db.lookup(ip)
+		if err != nil {
+			g.logger.WithError(err).WithField("ip", ip.String()).Debug("geoip lookup failed")
+			continue
+		}
+		record.merge(found)
+	}
+	return record
+}
+
+//openGeoDatabase reads the file as mmdb (detected by the metadata marker) or csv
+func openGeoDatabase(file string) (geoDatabase, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(content, []byte("\xAB\xCD\xEFMaxMind.com")) {
+		//the file is read to memory, so the database can be swapped while lookups are running
+		reader, err := maxminddb.FromBytes(content)
+		if err != nil {
+			return nil, err
+		}
+		return &mmdbDatabase{reader: reader}, nil
+	}
+	return parseGeoCSV(bytes.NewReader(content))
+}
+
+//mmdbDatabase reads MaxMind format databases
+type mmdbDatabase struct {
+	reader *maxminddb.Reader
+}
+
+//mmdbRecord contains fields of GeoIP2/GeoLite2 City, Country and ASN databases
+type mmdbRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	ASN uint   `maxminddb:"autonomous_system_number"`
+	Org string `maxminddb:"autonomous_system_organization"`
+}
+
+func (db *mmdbDatabase) lookup(ip net.IP) (GeoRecord, error) {
+	//ipv6 can't be looked up in ipv4 only databases
+	if ip.To4() == nil && db.reader.Metadata.IPVersion == 4 {
+		return GeoRecord{}, nil
+	}
+	record := mmdbRecord{}
+	if err := db.reader.Lookup(ip, &record); err != nil {
+		return GeoRecord{}, err
+	}
+	return GeoRecord{
+		Country: record.Country.ISOCode,
+		City:    record.City.Names["en"],
+		ASN:     record.ASN,
+		Org:     record.Org,
+	}, nil
+}
+
+//csvDatabase contains sorted, non overlapping ranges
+type csvDatabase struct {
+	ranges []geoRange
+}
+
+type geoRange struct {
+	start, end net.IP
+	record     GeoRecord
+}
+
+//parseGeoCSV parses csv range database (see GeoIP). Nested ranges are split, so the most specific
+//range wins, the first one of identical ranges is used.
+func parseGeoCSV(r io.Reader) (*csvDatabase, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	ranges := make([]geoRange, 0)
+	for record := 1; ; record++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rng, ok, err := parseGeoRange(fields)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %s", record, err)
+		}
+		if ok {
+			ranges = append(ranges, rng)
+		}
+	}
+	//enclosing ranges go before the nested ones
+	sort.SliceStable(ranges, func(i, k int) bool {
+		if c := bytes.Compare(ranges[i].start, ranges[k].start); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(ranges[i].end, ranges[k].end) > 0
+	})
+
+	//split nested ranges, so the lookup has to check a single range. Open ranges are kept on
+	//the stack (innermost last), next is the first ip which hasn't been assigned to a range yet
+	//(nil after the last ip of the address space).
+	db := &csvDatabase{ranges: make([]geoRange, 0, len(ranges))}
+	stack := make([]geoRange, 0)
+	var next net.IP
+	emit := func(rng geoRange, end net.IP) {
+		if next != nil && bytes.Compare(next, end) <= 0 {
+			db.ranges = append(db.ranges, geoRange{start: next, end: end, record: rng.record})
+		}
+	}
+	closeTop := func() {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		emit(top, top.end)
+		next = nextIP(top.end)
+		if bytes.Equal(next, make(net.IP, net.IPv6len)) {
+			next = nil
+		}
+	}
+	for _, rng := range ranges {
+		for len(stack) > 0 && bytes.Compare(stack[len(stack)-1].end, rng.start) < 0 {
+			closeTop()
+		}
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if bytes.Compare(rng.end, top.end) > 0 {
+				return nil, fmt.Errorf("range %s-%s overlaps %s-%s", rng.start, rng.end, top.start, top.end)
+			}
+			if bytes.Equal(rng.start, top.start) && bytes.Equal(rng.end, top.end) {
+				continue
+			}
+			emit(top, prevIP(rng.start))
+		}
+		stack = append(stack, rng)
+		next = rng.start
+	}
+	for len(stack) > 0 {
+		closeTop()
+	}
+	return db, nil
+}
+
+//parseGeoRange parses a single csv line, returns false for lines which aren't ranges
+func parseGeoRange(fields []string) (geoRange, bool, error) {
+	rng := geoRange{}
+	if _, network, err := net.ParseCIDR(fields[0]); err == nil {
+		rng.start = network.IP.To16()
+		rng.end = make(net.IP, net.IPv6len)
+		mask := network.Mask
+		if len(mask) == net.IPv4len {
+			mask = append(net.CIDRMask(96, 128)[:12], mask...)
+		}
+		for i := range rng.start {
+			rng.end[i] = rng.start[i] | ^mask[i]
+		}
+		fields = fields[1:]
+	} else if start := net.ParseIP(fields[0]); start != nil && len(fields) > 1 {
+		end := net.ParseIP(fields[1])
+		if end == nil {
+			return rng, false, fmt.Errorf("invalid end of the range %s", fields[1])
+		}
+		rng.start, rng.end = start.To16(), end.To16()
+		if bytes.Compare(rng.start, rng.end) > 0 {
+			return rng, false, fmt.Errorf("range %s-%s is reversed", fields[0], fields[1])
+		}
+		fields = fields[2:]
+	} else {
+		return rng, false, nil
+	}
+
+	get := func(i int) string {
+		if i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+	rng.record.Country = strings.ToUpper(get(0))
+	rng.record.City = get(1)
+	if asn := strings.TrimPrefix(strings.ToUpper(get(2)), "AS"); asn != "" {
+		n, err := strconv.ParseUint(asn, 10, 32)
+		if err != nil {
+			return rng, false, fmt.Errorf("invalid asn %s", get(2))
+		}
+		rng.record.ASN = uint(n)
+	}
+	rng.record.Org = get(3)
+	return rng, true, nil
+}
+
+func (db *csvDatabase) lookup(ip net.IP) (GeoRecord, error) {
+	ip = ip.To16()
+	//first range starting after the ip, the candidate is the one before it
+	i := sort.Search(len(db.ranges), func(i int) bool {
+		return bytes.Compare(db.ranges[i].start, ip) > 0
+	})
+	if i > 0 && bytes.Compare(ip, db.ranges[i-1].end) <= 0 {
+		return db.ranges[i-1].record, nil
+	}
+	return GeoRecord{}, nil
+}
+
+//prevIP returns the ip preceding the given one (16 bytes form)
+func prevIP(ip net.IP) net.IP {
+	prev := make(net.IP, len(ip))
+	copy(prev, ip)
+	for i := len(prev) - 1; i >= 0; i-- {
+		prev[i]--
+		if prev[i] != 0xff {
+			break
+		}
+	}
+	return prev
+}
+
+//nextIP returns the ip following the given one (16 bytes form)
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
@@ -0,0 +1,181 @@
+package judge
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//mmdbValue encodes the control byte of the MaxMind DB data section value
+func mmdbValue(kind byte, size int, payload []byte) []byte {
+	if size < 29 {
+		return append([]byte{kind<<5 | byte(size)}, payload...)
+	}
+	return append([]byte{kind<<5 | 29, byte(size - 29)}, payload...)
+}
+
+func mmdbString(s string) []byte {
+	return mmdbValue(2, len(s), []byte(s))
+}
+
+func mmdbUint(kind byte, n uint32) []byte {
+	payload := []byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	for len(payload) > 0 && payload[0] == 0 {
+		payload = payload[1:]
+	}
+	return mmdbValue(kind, len(payload), payload)
+}
+
+func mmdbMap(pairs ...[]byte) []byte {
+	result := mmdbValue(7, len(pairs)/2, nil)
+	for _, pair := range pairs {
+		result = append(result, pair...)
+	}
+	return result
+}
+
+//writeTestMMDB writes ipv4 database with a single record for 0.0.0.0/1
+func writeTestMMDB(t *testing.T, path string) {
+	record := mmdbMap(
+		mmdbString("country"), mmdbMap(mmdbString("iso_code"), mmdbString("DE")),
+		mmdbString("city"), mmdbMap(mmdbString("names"), mmdbMap(mmdbString("en"), mmdbString("Berlin"))),
+		mmdbString("autonomous_system_number"), mmdbUint(6, 64500),
+		mmdbString("autonomous_system_organization"), mmdbString("Example Org"),
+	)
+	metadata := mmdbMap(
+		mmdbString("node_count"), mmdbUint(6, 1),
+		mmdbString("record_size"), mmdbUint(5, 24),
+		mmdbString("ip_version"), mmdbUint(5, 4),
+		mmdbString("database_type"), mmdbString("Test"),
+	)
+	//single node, left record points to the data (node count + separator), right record is empty
+	content := []byte{0, 0, 17, 0, 0, 1}
+	content = append(content, make([]byte, 16)...)
+	content = append(content, record...)
+	content = append(content, []byte("\xAB\xCD\xEFMaxMind.com")...)
+	content = append(content, metadata...)
+	assert.NoError(t, ioutil.WriteFile(path, content, 0600))
+}
+
+func TestParseGeoCSV(t *testing.T) {
+	db, err := parseGeoCSV(strings.NewReader(`network,country,city,asn,org
+# comment
+1.2.3.0/24,de,Berlin,AS64500,Example Org
+1.2.4.0,1.2.5.255,US,,64501,"Other, Org"
+1.2.3.128/25,FR
+2001:db8::/32,NL
+10.0.0.0/8
+5.0.0.0/8,US
+5.6.0.0/16,DE
+5.6.7.0/24,AT
+5.6.0.0/16,CH
+ffff::/16,JP
+ffff:ffff::/32,KR
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	tests := []struct {
+		ip     string
+		record GeoRecord
+	}{
+		{"1.2.3.4", GeoRecord{Country: "DE", City: "Berlin", ASN: 64500, Org: "Example Org"}},
+		{"1.2.3.127", GeoRecord{Country: "DE", City: "Berlin", ASN: 64500, Org: "Example Org"}},
+		{"1.2.3.200", GeoRecord{Country: "FR"}},
+		{"1.2.3.255", GeoRecord{Country: "FR"}},
+		{"1.2.5.255", GeoRecord{Country: "US", ASN: 64501, Org: "Other, Org"}},
+		{"1.2.6.0", GeoRecord{}},
+		{"2001:db8::1", GeoRecord{Country: "NL"}},
+		{"10.1.1.1", GeoRecord{}},
+		{"0.0.0.1", GeoRecord{}},
+		//most specific range wins, the enclosing ranges are split around it
+		{"5.1.1.1", GeoRecord{Country: "US"}},
+		{"5.6.6.255", GeoRecord{Country: "DE"}},
+		{"5.6.7.8", GeoRecord{Country: "AT"}},
+		{"5.6.8.0", GeoRecord{Country: "DE"}},
+		{"5.7.0.0", GeoRecord{Country: "US"}},
+		//ranges ending with the last address
+		{"ffff::1", GeoRecord{Country: "JP"}},
+		{"ffff:ffff::1", GeoRecord{Country: "KR"}},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", GeoRecord{Country: "KR"}},
+	}
+	for _, test := range tests {
+		record, err := db.lookup(net.ParseIP(test.ip))
+		assert.NoError(t, err)
+		assert.Equal(t, test.record, record, test.ip)
+	}
+
+	_, err = parseGeoCSV(strings.NewReader("1.2.3.0/24,DE,,ASX"))
+	assert.Error(t, err)
+	_, err = parseGeoCSV(strings.NewReader("1.2.3.4,1.2.3.0,DE"))
+	assert.Error(t, err)
+	_, err = parseGeoCSV(strings.NewReader("1.2.3.0,1.2.3.10,DE\n1.2.3.5,1.2.3.20,FR"))
+	assert.EqualError(t, err, "range 1.2.3.5-1.2.3.20 overlaps 1.2.3.0-1.2.3.10")
+}
+
+func TestGeoIP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "geoip")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	mmdbPath := filepath.Join(dir, "test.mmdb")
+	csvPath := filepath.Join(dir, "asn.csv")
+	writeTestMMDB(t, mmdbPath)
+	assert.NoError(t, ioutil.WriteFile(csvPath, []byte("1.2.3.0/24,,,AS64501,Csv Org\n200.0.0.0/8,BR,,64502,Other\n"), 0600))
+
+	geo := NewGeoIP(mmdbPath, csvPath)
+	assert.Equal(t, GeoRecord{}, geo.Lookup(net.ParseIP("1.2.3.4")), "nothing is loaded before Load")
+	if !assert.NoError(t, geo.Load()) {
+		return
+	}
+	//first database wins for every field
+	assert.Equal(t, GeoRecord{Country: "DE", City: "Berlin", ASN: 64500, Org: "Example Org"}, geo.Lookup(net.ParseIP("1.2.3.4")))
+	assert.Equal(t, GeoRecord{Country: "BR", ASN: 64502, Org: "Other"}, geo.Lookup(net.ParseIP("200.1.1.1")))
+	assert.Equal(t, GeoRecord{}, geo.Lookup(net.ParseIP("2001:db8::1")))
+
+	//invalid database keeps the current ones
+	assert.NoError(t, ioutil.WriteFile(csvPath, []byte("200.0.0.0/8,BR,,ASX\n"), 0600))
+	assert.Error(t, geo.Load())
+	assert.Equal(t, "BR", geo.Lookup(net.ParseIP("200.1.1.1")).Country)
+
+	//changed file is reloaded by Run
+	assert.NoError(t, ioutil.WriteFile(csvPath, []byte("200.0.0.0/8,AR\n"), 0600))
+	future := time.Now().Add(time.Hour)
+	assert.NoError(t, os.Chtimes(csvPath, future, future))
+	geo.Interval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go geo.Run(ctx)
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && geo.Lookup(net.ParseIP("200.1.1.1")).Country != "AR" {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, "AR", geo.Lookup(net.ParseIP("200.1.1.1")).Country)
+}
+
+func TestJudge_AnalyzeGeoIP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "geoip")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "geo.csv")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("5.6.7.0/24,DE,Berlin,AS64500,Example Org\n"), 0600))
+
+	j := newTestJudge(nil)
+	j.GeoIP = NewGeoIP(path)
+	assert.NoError(t, j.GeoIP.Load())
+
+	result := j.Analyze(newJudgeRequest("5.6.7.8:4000", "1.2.3.4", nil))
+	assert.Equal(t, "DE", result.Country)
+	assert.Equal(t, "Berlin", result.City)
+	assert.Equal(t, uint(64500), result.ASN)
+	assert.Equal(t, "Example Org", result.Org)
+
+	encoded, _ := result.MarshalJSON()
+	assert.Contains(t, string(encoded), `"city":"Berlin","asn":64500,"org":"Example Org"`)
+}
@@ -1,7 +1,12 @@
 package judge
 
 import (
+	"net"
+	"net/http"
 	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/alekc/proxy"
 	"github.com/sirupsen/logrus"
@@ -12,11 +17,51 @@ const version = "0.1.0"
 type Judge struct {
 	ListenAddress string
 	//Set to true if you want support for judge being behind the cloudflare infrastructure
+	//(ignored if Edge is set)
 	CloudFlareSupport bool
-	//List of trusted gateways. If your judge instance is behind some load-balancer/gateway
+	//Cloudflare networks, embedded list is used unless sources are set (see CloudflareRangeURLs)
+	CloudflareRanges *Ranges
+	//Edge network in front of the judge (see NewEdge)
+	Edge Edge
+	//Fingerprints used to identify proxy software (see LoadFingerprints)
+	Fingerprints *FingerprintTable
+	//Location and owner of the proxy ip, disabled if nil (see NewGeoIP)
+	GeoIP *GeoIP
+	//Tags the proxy ip with categories (datacenter, tor, vpn), disabled if nil (see NewClassifier)
+	Classifier *Classifier
+	//List of trusted gateways (ips or cidrs). If your judge instance is behind some load-balancer/gateway
 	//which adds it's ip to x-forwarded-for header you might want to add it here.
+	//The field is only read when the server starts (invalid entries prevent it from starting), use
+	//SetTrustedGateways to change the gateways of a running judge.
 	TrustedGatewaysIps []string
-	logger             *logrus.Logger
+	//Number of gateways in front of the judge which are trusted regardless of their ip (the peer
+	//and the rightmost forwarding entries). Useful for load balancers with changing addresses.
+	TrustedHops int
+	//Encode the anonymity level as a name (i.e. "elite") instead of a number in the response
+	AnonymityNames bool
+	//Reverse dns resolver used to look for proxy markers in the hostname (net.LookupAddr by default)
+	LookupAddr func(ip string) ([]string, error)
+
+	//Server limits (see http.Server). Zero means no limit.
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	//Time given to active requests when the context passed to Serve is cancelled
+	ShutdownTimeout time.Duration
+
+	//header and hostname rules, see Rules and SetRules
+	rules atomic.Value
+	//parsed TrustedGatewaysIps, see SetTrustedGateways
+	gateways atomic.Value
+	//edge used when only CloudFlareSupport is set
+	cloudflareEdge atomic.Value
+
+	logger  *logrus.Logger
+	mu      sync.Mutex
+	server  *http.Server
+	stopped chan struct{}
 }
 
 //Create new Judge instance
@@ -24,6 +69,16 @@ func Create() *Judge {
 	obj := new(Judge)
 	obj.ListenAddress = ":8080"
 	obj.CloudFlareSupport = true
+	obj.CloudflareRanges = NewRanges(DefaultCloudflareRanges())
+	obj.LookupAddr = net.LookupAddr
+	obj.Fingerprints = DefaultFingerprintTable()
+	obj.rules.Store(DefaultRuleSet())
+	obj.ReadTimeout = 10 * time.Second
+	obj.ReadHeaderTimeout = 5 * time.Second
+	obj.WriteTimeout = 10 * time.Second
+	obj.IdleTimeout = 60 * time.Second
+	obj.MaxHeaderBytes = 64 * 1024
+	obj.ShutdownTimeout = 10 * time.Second
 
 	//default logger (only errors are visible)
 	obj.logger = logrus.New()
@@ -35,9 +90,20 @@ func Create() *Judge {
 
 func (j *Judge) SetLogger(log *logrus.Logger) {
 	j.logger = log
+	j.CloudflareRanges.SetLogger(log)
+	if j.GeoIP != nil {
+		j.GeoIP.SetLogger(log)
+	}
+	if j.Classifier != nil {
+		j.Classifier.SetLogger(log)
+	}
+	if edge, ok := j.Edge.(interface{ SetLogger(*logrus.Logger) }); ok {
+		edge.SetLogger(log)
+	}
 }
 func NewJudgement() *proxy.Judgement {
 	return &proxy.Judgement{
 		Messages: make([]string, 0),
+		Findings: make([]proxy.Finding, 0),
 	}
 }
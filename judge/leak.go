@@ -0,0 +1,156 @@
+package judge
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alekc/proxy"
+)
+
+var (
+	//octets separated by dots, dashes or underscores. Matches are greedy, so 1.2.3.4 isn't found in 11.2.3.45
+	octetsRe = regexp.MustCompile(`[0-9]+(?:[.\-_][0-9]+)+`)
+	//ipv6 candidates (brackets and ports are left out)
+	ipv6Re   = regexp.MustCompile(`[0-9a-f.]*:[0-9a-f:.]*`)
+	wordRe   = regexp.MustCompile(`[0-9a-z]+`)
+	base64Re = regexp.MustCompile(`[A-Za-z0-9+/_\-]{6,}={0,2}`)
+
+	base64Encodings = []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding}
+)
+
+//leakDetector looks for the real ip in header values in all known encodings (see proxy.IPEncoding)
+type leakDetector struct {
+	raw string
+	ip  net.IP
+	//nil for ipv6 addresses
+	v4 net.IP
+}
+
+//newLeakDetector creates detector of the real ip. If the real ip isn't a valid ip address it's
+//searched as a plain string.
+func newLeakDetector(realIP string) *leakDetector {
+	d := &leakDetector{raw: realIP, ip: net.ParseIP(realIP)}
+	if d.ip != nil {
+		d.v4 = d.ip.To4()
+	}
+	return d
+}
+
+//find returns the first occurrence of the real ip in the value and its encoding
+func (d *leakDetector) find(value string) (string, proxy.IPEncoding, bool) {
+	if d.ip == nil {
+		return d.raw, proxy.EncodingPlain, d.raw != "" && strings.Contains(value, d.raw)
+	}
+	//percent encoded values, i.e. for=%5B2001:db8::1%5D
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		value = unescaped
+	}
+	if token, encoding, ok := d.findText(strings.ToLower(value)); ok {
+		return token, encoding, true
+	}
+	for _, token := range base64Re.FindAllString(value, -1) {
+		if d.matchBase64(token) {
+			return token, proxy.EncodingBase64, true
+		}
+	}
+	return "", "", false
+}
+
+//findText looks for textual and numeric forms of the ip in the lowercase value
+func (d *leakDetector) findText(value string) (string, proxy.IPEncoding, bool) {
+	for _, token := range ipv6Re.FindAllString(value, -1) {
+		if strings.Count(token, ":") < 2 {
+			continue
+		}
+		ip := net.ParseIP(strings.TrimRight(token, "."))
+		if ip == nil || !ip.Equal(d.ip) {
+			continue
+		}
+		if d.v4 != nil {
+			return token, proxy.EncodingIPv4Mapped, true
+		}
+		return token, proxy.EncodingPlain, true
+	}
+	if d.v4 != nil {
+		for _, token := range octetsRe.FindAllString(value, -1) {
+			if encoding, ok := d.matchOctets(token); ok {
+				return token, encoding, true
+			}
+		}
+	}
+	for _, token := range wordRe.FindAllString(value, -1) {
+		if encoding, ok := d.matchNumber(token); ok {
+			return token, encoding, true
+		}
+	}
+	return "", "", false
+}
+
+//matchOctets checks dotted, dashed and reversed forms of ipv4 (octets can be zero padded)
+func (d *leakDetector) matchOctets(token string) (proxy.IPEncoding, bool) {
+	parts := strings.FieldsFunc(token, func(r rune) bool { return r == '.' || r == '-' || r == '_' })
+	if len(parts) != 4 || strings.Trim(token, "0123456789.") != "" && strings.Trim(token, "0123456789-_") != "" {
+		return "", false
+	}
+	octets := make([]byte, 4)
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 8)
+		if err != nil {
+			return "", false
+		}
+		octets[i] = byte(n)
+	}
+	switch {
+	case bytes.Equal(octets, d.v4) && strings.Contains(token, "."):
+		return proxy.EncodingPlain, true
+	case bytes.Equal(octets, d.v4):
+		return proxy.EncodingDashed, true
+	case bytes.Equal(octets, []byte{d.v4[3], d.v4[2], d.v4[1], d.v4[0]}):
+		return proxy.EncodingReversed, true
+	}
+	return "", false
+}
+
+//matchNumber checks hexadecimal and decimal forms of the ip
+func (d *leakDetector) matchNumber(token string) (proxy.IPEncoding, bool) {
+	address := d.ip.To16()
+	if d.v4 != nil {
+		address = d.v4
+	}
+	digits := strings.TrimPrefix(token, "0x")
+	if len(digits) == 2*len(address) {
+		if decoded, err := hex.DecodeString(digits); err == nil && bytes.Equal(decoded, address) {
+			return proxy.EncodingHex, true
+		}
+	}
+	if d.v4 != nil && d.v4[0] != 0 {
+		if n, err := strconv.ParseUint(token, 10, 32); err == nil &&
+			uint32(n) == uint32(d.v4[0])<<24|uint32(d.v4[1])<<16|uint32(d.v4[2])<<8|uint32(d.v4[3]) {
+			return proxy.EncodingDecimal, true
+		}
+	}
+	return "", false
+}
+
+//matchBase64 checks if the token is base64 of the binary or textual form of the ip
+func (d *leakDetector) matchBase64(token string) bool {
+	for _, encoding := range base64Encodings {
+		decoded, err := encoding.DecodeString(token)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(decoded, d.ip.To16()) || d.v4 != nil && bytes.Equal(decoded, d.v4) {
+			return true
+		}
+		if _, _, ok := d.findText(strings.ToLower(string(decoded))); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package judge
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/alekc/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeakDetector_Find(t *testing.T) {
+	tests := []struct {
+		realIP   string
+		value    string
+		encoding proxy.IPEncoding
+		token    string
+	}{
+		{"1.2.3.4", "1.2.3.4", proxy.EncodingPlain, "1.2.3.4"},
+		{"1.2.3.4", "5.6.7.8, 1.2.3.4:5123", proxy.EncodingPlain, "1.2.3.4"},
+		{"1.2.3.4", "client=001.002.003.004", proxy.EncodingPlain, "001.002.003.004"},
+		{"1.2.3.4", "host-1-2-3-4.example.com", proxy.EncodingDashed, "1-2-3-4"},
+		{"1.2.3.4", "4.3.2.1.in-addr.arpa", proxy.EncodingReversed, "4.3.2.1"},
+		{"1.2.3.4", "id=0x01020304", proxy.EncodingHex, "0x01020304"},
+		{"1.2.3.4", "01020304", proxy.EncodingHex, "01020304"},
+		{"1.2.3.4", "16909060", proxy.EncodingDecimal, "16909060"},
+		{"1.2.3.4", "::ffff:1.2.3.4", proxy.EncodingIPv4Mapped, "::ffff:1.2.3.4"},
+		{"1.2.3.4", `for="[::FFFF:102:304]:4711"`, proxy.EncodingIPv4Mapped, "::ffff:102:304"},
+		{"1.2.3.4", "token MS4yLjMuNA==", proxy.EncodingBase64, "MS4yLjMuNA=="},
+		{"1.2.3.4", "AQIDBA", proxy.EncodingBase64, "AQIDBA"},
+		{"2001:db8::1", `for="[2001:DB8:0:0::1]:4711"`, proxy.EncodingPlain, "2001:db8:0:0::1"},
+		{"2001:db8::1", "for=%5B2001:db8::1%5D", proxy.EncodingPlain, "2001:db8::1"},
+		{"2001:db8::1", "20010db8000000000000000000000001", proxy.EncodingHex, "20010db8000000000000000000000001"},
+		{"2001:db8::1", "MjAwMTpkYjg6OjE=", proxy.EncodingBase64, "MjAwMTpkYjg6OjE="},
+		{"not-an-ip", "x not-an-ip", proxy.EncodingPlain, "not-an-ip"},
+	}
+	for _, test := range tests {
+		token, encoding, ok := newLeakDetector(test.realIP).find(test.value)
+		if assert.True(t, ok, test.value) {
+			assert.Equal(t, test.encoding, encoding, test.value)
+			assert.Equal(t, test.token, token, test.value)
+		}
+	}
+
+	misses := []struct {
+		realIP string
+		value  string
+	}{
+		{"1.2.3.4", "11.2.3.45"},
+		{"1.2.3.4", "1.2.3.4.5"},
+		{"1.2.3.4", "1.2-3.4"},
+		{"1.2.3.4", "169090601"},
+		{"1.2.3.4", "0x010203040"},
+		{"1.2.3.4", "2001:db8::1"},
+		{"2001:db8::1", "2001:db8::10"},
+		{"2001:db8::1", "1.2.3.4"},
+	}
+	for _, test := range misses {
+		_, _, ok := newLeakDetector(test.realIP).find(test.value)
+		assert.False(t, ok, test.value)
+	}
+}
+
+func TestJudge_CheckIPInHeadersEncoded(t *testing.T) {
+	j := newTestJudge(nil)
+	findings := j.checkIPInHeaders(http.Header{
+		"X-Client-Id": {"0x01020304"},
+		"X-Other":     {"11.2.3.45"},
+	}, "1.2.3.4")
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, "X-Client-Id", findings[0].Header)
+		assert.Equal(t, proxy.EncodingHex, findings[0].Encoding)
+		assert.Equal(t, "Found real ip (hex) in the header [X-Client-Id]", findings[0].Message())
+	}
+}
@@ -0,0 +1,257 @@
+package judge
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+//RangeSet is an immutable set of ip networks stored in a binary prefix tree, so a lookup
+//takes at most 32 (128 for ipv6) steps regardless of the number of networks.
+//It is safe for concurrent use.
+type RangeSet struct {
+	v4  *rangeNode
+	v6  *rangeNode
+	len int
+}
+
+type rangeNode struct {
+	children [2]*rangeNode
+	//network ends in this node, all addresses below are contained
+	terminal bool
+}
+
+//NewRangeSet creates set containing given networks
+func NewRangeSet(networks []*net.IPNet) *RangeSet {
+	set := &RangeSet{v4: &rangeNode{}, v6: &rangeNode{}}
+	for _, network := range networks {
+		ones, bits := network.Mask.Size()
+		root, ip := set.v6, network.IP.To16()
+		if bits == 8*net.IPv4len {
+			root, ip = set.v4, network.IP.To4()
+		}
+		if ip == nil || bits == 0 {
+			continue
+		}
+		node := root
+		for i := 0; i < ones && !node.terminal; i++ {
+			bit := ip[i/8] >> uint(7-i%8) & 1
+			if node.children[bit] == nil {
+				node.children[bit] = &rangeNode{}
+			}
+			node = node.children[bit]
+		}
+		node.terminal = true
+		set.len++
+	}
+	return set
+}
+
+//Contains checks if ip belongs to one of the networks
+func (s *RangeSet) Contains(ip net.IP) bool {
+	if s == nil || ip == nil {
+		return false
+	}
+	node := s.v6
+	if ip4 := ip.To4(); ip4 != nil {
+		node, ip = s.v4, ip4
+	}
+	for i := 0; i < 8*len(ip); i++ {
+		if node.terminal {
+			return true
+		}
+		node = node.children[ip[i/8]>>uint(7-i%8)&1]
+		if node == nil {
+			return false
+		}
+	}
+	return node.terminal
+}
+
+//Len returns number of networks in the set
+func (s *RangeSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return s.len
+}
+
+//ParseRanges reads one cidr or ip per line. Tor exit lists (ExitAddress lines of
+//https://check.torproject.org/exit-addresses) are supported as well. Empty lines and comments (#)
+//are ignored, malformed lines are skipped and returned as errors.
+func ParseRanges(r io.Reader) ([]*net.IPNet, []error) {
+	networks := make([]*net.IPNet, 0)
+	errs := make([]error, 0)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "ExitNode", "Published", "LastStatus":
+			//other lines of tor exit list
+			continue
+		case "ExitAddress":
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			errs = append(errs, fmt.Errorf("line %d: missing address", line))
+			continue
+		}
+		parsed, err := ParseNetworks(fields[:1])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %s", line, err))
+			continue
+		}
+		networks = append(networks, parsed...)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return networks, errs
+}
+
+//Ranges is a RangeSet loaded from files or urls and refreshed periodically. The set is swapped
+//atomically, lookups are never blocked by a refresh.
+type Ranges struct {
+	//Files or urls (http/https) with one cidr per line. All sources are merged.
+	Sources []string
+	//Refresh interval used by Run
+	Interval time.Duration
+	Client   *http.Client
+
+	set    atomic.Value
+	logger *logrus.Logger
+}
+
+//NewRanges creates ranges initialized with the fallback set, used until the sources are loaded
+func NewRanges(fallback *RangeSet, sources ...string) *Ranges {
+	ranges := &Ranges{
+		Sources:  sources,
+		Interval: 24 * time.Hour,
+		Client:   &http.Client{Timeout: 30 * time.Second},
+	}
+	if fallback == nil {
+		fallback = NewRangeSet(nil)
+	}
+	ranges.set.Store(fallback)
+
+	//default logger (only errors are visible)
+	ranges.logger = logrus.New()
+	ranges.logger.Out = os.Stdout
+	ranges.logger.SetLevel(logrus.ErrorLevel)
+	return ranges
+}
+
+//SetLogger sets the logger used to report loading problems
+func (r *Ranges) SetLogger(log *logrus.Logger) {
+	r.logger = log
+}
+
+//Set returns the current set
+func (r *Ranges) Set() *RangeSet {
+	return r.set.Load().(*RangeSet)
+}
+
+//Contains checks if ip belongs to the current set
+func (r *Ranges) Contains(ip net.IP) bool {
+	return r.Set().Contains(ip)
+}
+
+//Load reads all sources and replaces the current set. If any source fails (or nothing valid is
+//found) the current set is kept.
+func (r *Ranges) Load(ctx context.Context) error {
+	if len(r.Sources) == 0 {
+		return nil
+	}
+	networks := make([]*net.IPNet, 0)
+	for _, source := range r.Sources {
+		found, err := r.loadSource(ctx, source)
+		if err != nil {
+			r.logger.
+				WithError(err).
+				WithField("source", source).
+				Error("couldn't load ip ranges, keeping the current ones")
+			return err
+		}
+		networks = append(networks, found...)
+	}
+	if len(networks) == 0 {
+		err := fmt.Errorf("no valid ip ranges found")
+		r.logger.WithError(err).Error("couldn't load ip ranges, keeping the current ones")
+		return err
+	}
+	set := NewRangeSet(networks)
+	r.set.Store(set)
+	r.logger.WithField("ranges", set.Len()).Debug("ip ranges loaded")
+	return nil
+}
+
+//loadSource reads a single file or url
+func (r *Ranges) loadSource(ctx context.Context, source string) ([]*net.IPNet, error) {
+	var reader io.Reader
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		req, err := http.NewRequest(http.MethodGet, source, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := r.Client.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+		reader = resp.Body
+	} else {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	networks, errs := ParseRanges(io.LimitReader(reader, 16*1024*1024))
+	for _, err := range errs {
+		r.logger.
+			WithError(err).
+			WithField("source", source).
+			Warn("skipping malformed ip range")
+	}
+	return networks, nil
+}
+
+//Run refreshes the ranges every Interval until the context is cancelled
+func (r *Ranges) Run(ctx context.Context) {
+	if len(r.Sources) == 0 || r.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = r.Load(ctx)
+		}
+	}
+}
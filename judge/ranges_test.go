@@ -0,0 +1,150 @@
+package judge
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeSet_Contains(t *testing.T) {
+	networks, errs := ParseRanges(strings.NewReader(`
+# cloudflare
+173.245.48.0/20
+10.0.0.0/8 # private
+10.1.0.0/16
+192.168.1.1/32
+2400:cb00::/32
+not a range
+300.1.1.1/8
+`))
+	assert.Len(t, errs, 2)
+	set := NewRangeSet(networks)
+	assert.Equal(t, 5, set.Len())
+
+	tests := map[string]bool{
+		"173.245.48.0":          true,
+		"173.245.63.255":        true,
+		"173.245.64.0":          false,
+		"10.200.1.1":            true,
+		"10.1.1.1":              true,
+		"11.0.0.1":              false,
+		"192.168.1.1":           true,
+		"192.168.1.2":           false,
+		"::ffff:173.245.48.1":   true,
+		"2400:cb00::1":          true,
+		"2400:cb01::1":          false,
+		"2606:4700::1":          false,
+		"::1":                   false,
+		"2400:cb00:ffff:ffff::": true,
+	}
+	for ip, expected := range tests {
+		assert.Equal(t, expected, set.Contains(net.ParseIP(ip)), ip)
+	}
+	assert.False(t, set.Contains(nil))
+	assert.False(t, NewRangeSet(nil).Contains(net.ParseIP("1.2.3.4")))
+}
+
+func TestDefaultCloudflareRanges(t *testing.T) {
+	set := DefaultCloudflareRanges()
+	assert.Equal(t, 21, set.Len())
+	assert.True(t, set.Contains(net.ParseIP("104.16.0.1")))
+	assert.True(t, set.Contains(net.ParseIP("2606:4700::6810:84e5")))
+	assert.False(t, set.Contains(net.ParseIP("8.8.8.8")))
+}
+
+func TestRanges_Load(t *testing.T) {
+	v4 := "198.51.100.0/24\ninvalid\n"
+	status := http.StatusOK
+	mu := sync.Mutex{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(status)
+		fmt.Fprint(w, v4)
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "judge")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	v6 := filepath.Join(dir, "ips-v6")
+	assert.NoError(t, ioutil.WriteFile(v6, []byte("2001:db8::/32\n"), 0600))
+
+	ranges := NewRanges(DefaultCloudflareRanges(), srv.URL, v6)
+	//fallback is used until the ranges are loaded
+	assert.True(t, ranges.Contains(net.ParseIP("104.16.0.1")))
+
+	assert.NoError(t, ranges.Load(context.Background()))
+	assert.Equal(t, 2, ranges.Set().Len())
+	assert.False(t, ranges.Contains(net.ParseIP("104.16.0.1")))
+	assert.True(t, ranges.Contains(net.ParseIP("198.51.100.7")))
+	assert.True(t, ranges.Contains(net.ParseIP("2001:db8::1")))
+
+	//failed refresh keeps the current set
+	mu.Lock()
+	status = http.StatusInternalServerError
+	mu.Unlock()
+	assert.Error(t, ranges.Load(context.Background()))
+	assert.True(t, ranges.Contains(net.ParseIP("198.51.100.7")))
+
+	//sources without valid ranges are refused as well
+	mu.Lock()
+	status, v4 = http.StatusOK, "garbage"
+	mu.Unlock()
+	ranges.Sources = []string{srv.URL}
+	assert.Error(t, ranges.Load(context.Background()))
+	assert.True(t, ranges.Contains(net.ParseIP("198.51.100.7")))
+
+	ranges.Sources = []string{filepath.Join(dir, "missing")}
+	assert.Error(t, ranges.Load(context.Background()))
+}
+
+func TestRanges_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "judge")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ranges")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("198.51.100.0/24\n"), 0600))
+
+	ranges := NewRanges(nil, path)
+	ranges.Interval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ranges.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !ranges.Contains(net.ParseIP("198.51.100.1")) {
+		if time.Now().After(deadline) {
+			t.Fatal("ranges weren't refreshed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+func TestJudge_CloudflareRangesPerJudge(t *testing.T) {
+	first := newTestJudge(nil)
+	first.CloudFlareSupport = true
+	second := newTestJudge(nil)
+	second.CloudFlareSupport = true
+	second.CloudflareRanges = NewRanges(NewRangeSet(nil))
+
+	headers := map[string]string{"X-Forwarded-For": "173.245.48.5"}
+	assert.Empty(t, first.Analyze(newJudgeRequest("173.245.48.1:4000", "1.2.3.4", headers)).Findings)
+	assert.NotEmpty(t, second.Analyze(newJudgeRequest("173.245.48.1:4000", "1.2.3.4", headers)).Findings)
+}
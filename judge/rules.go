@@ -0,0 +1,365 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alekc/proxy"
+	"gopkg.in/yaml.v2"
+)
+
+//Implication defines what a matched rule says about the proxy
+type Implication string
+
+//Known implications
+const (
+	//ImpliesProxyUsage - usage of a proxy is visible
+	ImpliesProxyUsage Implication = "proxy_usage"
+	//ImpliesIPLeak - real ip of the client is visible
+	ImpliesIPLeak Implication = "ip_leak"
+)
+
+//HeaderRule matches request headers. Either Header (exact name) or HeaderPattern (regular
+//expression of the name) has to be set, ValuePattern is optional.
+type HeaderRule struct {
+	Header        string         `json:"header,omitempty" yaml:"header,omitempty"`
+	HeaderPattern string         `json:"header_pattern,omitempty" yaml:"header_pattern,omitempty"`
+	ValuePattern  string         `json:"value_pattern,omitempty" yaml:"value_pattern,omitempty"`
+	Implies       Implication    `json:"implies" yaml:"implies"`
+	Severity      proxy.Severity `json:"severity,omitempty" yaml:"severity,omitempty"`
+	//Weight in the score, the weight of the severity is used if not set (see ScoringPolicy)
+	Weight *float64 `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+//HostnameRule matches the reverse hostname of the proxy with a regular expression
+type HostnameRule struct {
+	Pattern  string         `json:"pattern" yaml:"pattern"`
+	Implies  Implication    `json:"implies,omitempty" yaml:"implies,omitempty"`
+	Severity proxy.Severity `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Weight   *float64       `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+//Rules is the content of the rules file
+type Rules struct {
+	Headers   []HeaderRule   `json:"headers" yaml:"headers"`
+	Hostnames []HostnameRule `json:"hostnames" yaml:"hostnames"`
+	//Headers which are expected in requests, other headers are logged in debug output
+	KnownHeaders []string `json:"known_headers" yaml:"known_headers"`
+	//Policy deciding the anonymity level, DefaultScoringPolicy is used for unset values
+	Scoring *ScoringPolicy `json:"scoring,omitempty" yaml:"scoring,omitempty"`
+}
+
+//DefaultRules returns the built in rules
+func DefaultRules() Rules {
+	rules := Rules{
+		Headers:   make([]HeaderRule, 0),
+		Hostnames: make([]HostnameRule, 0),
+		KnownHeaders: []string{
+			"Connection", "Accept-Encoding", "Cf-Ipcountry", "Accept", "Accept-Language", "Cf-Ray",
+			"X-Forwarded-Proto", "Upgrade-Insecure-Requests", "Cache-Control", "Cookie", "Cf-Connecting-Ip",
+			"Cf-Visitor", "Content-Type", "Content-Length", "User-Agent", "Via", "X-Forwarded-For",
+			"X-Proxy-Id", "Dnt",
+		},
+	}
+	for _, header := range []string{"Client-Ip", "HTTP_CLIENT_IP", "FORWARDED", "FORWARDED-FOR",
+		"FORWARDED-FOR-IP", "X-FORWARDED", "X-FORWARDED-FOR", "PROXY_CONNECTION", "Via", "X-Proxy-Id",
+		"X-Bluecoat-Via", "X-Iwproxy"} {
+		rules.Headers = append(rules.Headers, HeaderRule{Header: header, Implies: ImpliesProxyUsage})
+	}
+	for _, hostname := range []string{"cache", "squid", "proxy"} {
+		rules.Hostnames = append(rules.Hostnames, HostnameRule{Pattern: hostname, Implies: ImpliesProxyUsage})
+	}
+	return rules
+}
+
+//RuleSet is a validated and compiled Rules. It is immutable and safe for concurrent use.
+type RuleSet struct {
+	headers      []compiledHeaderRule
+	hostnames    []compiledHostnameRule
+	knownHeaders map[string]bool
+	policy       ScoringPolicy
+}
+
+type compiledHeaderRule struct {
+	HeaderRule
+	header  string
+	name    *regexp.Regexp
+	value   *regexp.Regexp
+	finding proxy.FindingKind
+}
+
+type compiledHostnameRule struct {
+	HostnameRule
+	re *regexp.Regexp
+}
+
+//NewRuleSet validates and compiles the rules
+func NewRuleSet(rules Rules) (*RuleSet, error) {
+	set := &RuleSet{knownHeaders: make(map[string]bool)}
+	for i, rule := range rules.Headers {
+		compiled := compiledHeaderRule{HeaderRule: rule}
+		switch {
+		case rule.Header != "" && rule.HeaderPattern != "":
+			return nil, fmt.Errorf("header rule %d: header and header_pattern are exclusive", i)
+		case rule.Header != "":
+			compiled.header = textproto.CanonicalMIMEHeaderKey(rule.Header)
+		case rule.HeaderPattern != "":
+			re, err := regexp.Compile("(?i)" + rule.HeaderPattern)
+			if err != nil {
+				return nil, fmt.Errorf("header rule %d: %s", i, err)
+			}
+			compiled.name = re
+		default:
+			return nil, fmt.Errorf("header rule %d: header or header_pattern is required", i)
+		}
+		if rule.ValuePattern != "" {
+			re, err := regexp.Compile(rule.ValuePattern)
+			if err != nil {
+				return nil, fmt.Errorf("header rule %d: %s", i, err)
+			}
+			compiled.value = re
+		}
+		switch rule.Implies {
+		case ImpliesProxyUsage:
+			compiled.finding = proxy.FindingProxyHeader
+			compiled.Severity = defaultSeverity(rule.Severity, proxy.SeverityMedium)
+		case ImpliesIPLeak:
+			compiled.finding = proxy.FindingRealIP
+			compiled.Severity = defaultSeverity(rule.Severity, proxy.SeverityHigh)
+		default:
+			return nil, fmt.Errorf("header rule %d: unknown implication %q", i, rule.Implies)
+		}
+		if !validSeverity(compiled.Severity) {
+			return nil, fmt.Errorf("header rule %d: unknown severity %q", i, rule.Severity)
+		}
+		if rule.Weight != nil && *rule.Weight < 0 {
+			return nil, fmt.Errorf("header rule %d: negative weight", i)
+		}
+		set.headers = append(set.headers, compiled)
+	}
+
+	for i, rule := range rules.Hostnames {
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("hostname rule %d: pattern is required", i)
+		}
+		//the hostname identifies the proxy, it can't contain the real ip
+		if rule.Implies != "" && rule.Implies != ImpliesProxyUsage {
+			return nil, fmt.Errorf("hostname rule %d: hostname can only imply %s", i, ImpliesProxyUsage)
+		}
+		re, err := regexp.Compile("(?i)" + rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("hostname rule %d: %s", i, err)
+		}
+		rule.Implies = ImpliesProxyUsage
+		rule.Severity = defaultSeverity(rule.Severity, proxy.SeverityLow)
+		if !validSeverity(rule.Severity) {
+			return nil, fmt.Errorf("hostname rule %d: unknown severity %q", i, rule.Severity)
+		}
+		if rule.Weight != nil && *rule.Weight < 0 {
+			return nil, fmt.Errorf("hostname rule %d: negative weight", i)
+		}
+		set.hostnames = append(set.hostnames, compiledHostnameRule{HostnameRule: rule, re: re})
+	}
+
+	for _, header := range rules.KnownHeaders {
+		set.knownHeaders[textproto.CanonicalMIMEHeaderKey(header)] = true
+	}
+
+	policy := ScoringPolicy{}
+	if rules.Scoring != nil {
+		policy = *rules.Scoring
+	}
+	var err error
+	if set.policy, err = policy.withDefaults(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+//DefaultRuleSet returns compiled DefaultRules
+func DefaultRuleSet() *RuleSet {
+	set, err := NewRuleSet(DefaultRules())
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+//defaultRuleSet is used by judges without rules, compiled only once
+var defaultRuleSet = DefaultRuleSet()
+
+//LoadRules reads the rules file. Yaml is used for .yaml and .yml files, json otherwise.
+//Unknown fields are refused, so typos don't silently disable a rule.
+func LoadRules(path string) (*RuleSet, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules := Rules{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(content, &rules)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&rules)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", path, err)
+	}
+	return NewRuleSet(rules)
+}
+
+//Policy returns the scoring policy of the rules
+func (s *RuleSet) Policy() *ScoringPolicy {
+	return &s.policy
+}
+
+//Known checks if the header is expected in requests (see Rules.KnownHeaders)
+func (s *RuleSet) Known(header string) bool {
+	return s.knownHeaders[textproto.CanonicalMIMEHeaderKey(header)]
+}
+
+//MatchHeaders returns findings of the header rules. Every header is reported once per finding kind.
+func (s *RuleSet) MatchHeaders(headers http.Header) []proxy.Finding {
+	findings := make([]proxy.Finding, 0)
+	found := make(map[string]bool)
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, rule := range s.headers {
+		for _, name := range names {
+			if rule.header != "" && rule.header != name || rule.name != nil && !rule.name.MatchString(name) {
+				continue
+			}
+			value := strings.Join(headers[name], ",")
+			if rule.value != nil && !rule.value.MatchString(value) {
+				continue
+			}
+			if found[string(rule.finding)+name] {
+				continue
+			}
+			found[string(rule.finding)+name] = true
+			marker := rule.Header
+			if marker == "" {
+				marker = rule.HeaderPattern
+			}
+			findings = append(findings, proxy.Finding{
+				Kind:     rule.finding,
+				Header:   name,
+				Value:    value,
+				Marker:   marker,
+				Severity: rule.Severity,
+				Weight:   rule.Weight,
+			})
+		}
+	}
+	return findings
+}
+
+//MatchHostnames returns findings of the hostname rules. Every hostname is reported once, by the
+//matching rule with the highest weight.
+func (s *RuleSet) MatchHostnames(hostnames []string) []proxy.Finding {
+	findings := make([]proxy.Finding, 0)
+	for _, name := range hostnames {
+		var best *compiledHostnameRule
+		for i := range s.hostnames {
+			rule := &s.hostnames[i]
+			if !rule.re.MatchString(name) {
+				continue
+			}
+			if best == nil || s.policy.weight(rule.Severity, rule.Weight) > s.policy.weight(best.Severity, best.Weight) {
+				best = rule
+			}
+		}
+		if best != nil {
+			findings = append(findings, proxy.Finding{
+				Kind:     proxy.FindingHostname,
+				Value:    name,
+				Marker:   best.Pattern,
+				Severity: best.Severity,
+				Weight:   best.Weight,
+			})
+		}
+	}
+	return findings
+}
+
+func defaultSeverity(severity, def proxy.Severity) proxy.Severity {
+	if severity == "" {
+		return def
+	}
+	return severity
+}
+
+func validSeverity(severity proxy.Severity) bool {
+	switch severity {
+	case proxy.SeverityLow, proxy.SeverityMedium, proxy.SeverityHigh:
+		return true
+	}
+	return false
+}
+
+//Rules returns rules currently used by the judge (DefaultRuleSet unless replaced)
+func (j *Judge) Rules() *RuleSet {
+	if rules, ok := j.rules.Load().(*RuleSet); ok {
+		return rules
+	}
+	return defaultRuleSet
+}
+
+//SetRules replaces the rules, requests in progress finish with the previous rules
+func (j *Judge) SetRules(rules *RuleSet) {
+	j.rules.Store(rules)
+}
+
+//ReloadRules loads the rules file and replaces the rules. Current rules are kept if the file is invalid.
+func (j *Judge) ReloadRules(path string) error {
+	rules, err := LoadRules(path)
+	if err != nil {
+		j.logger.WithError(err).WithField("path", path).Error("couldn't load rules, keeping the current ones")
+		return err
+	}
+	j.SetRules(rules)
+	j.logger.WithField("path", path).Info("rules loaded")
+	return nil
+}
+
+//WatchRules reloads the rules file whenever its modification time or size changes, checking every
+//interval until the context is cancelled
+func (j *Judge) WatchRules(ctx context.Context, path string, interval time.Duration) {
+	var lastMod time.Time
+	var lastSize int64 = -1
+	if info, err := os.Stat(path); err == nil {
+		lastMod, lastSize = info.ModTime(), info.Size()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(path)
+		if err != nil || (info.ModTime().Equal(lastMod) && info.Size() == lastSize) {
+			continue
+		}
+		lastMod, lastSize = info.ModTime(), info.Size()
+		_ = j.ReloadRules(path)
+	}
+}
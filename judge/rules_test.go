@@ -0,0 +1,156 @@
+package judge
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRules(t *testing.T) {
+	rules := DefaultRuleSet()
+	findings := rules.MatchHeaders(http.Header{
+		"X-Forwarded-For": {"1.2.3.4"},
+		"Via":             {"1.1 squid"},
+		"Accept":          {"*/*"},
+	})
+	assert.Equal(t, []proxy.Finding{
+		{Kind: proxy.FindingProxyHeader, Header: "X-Forwarded-For", Value: "1.2.3.4", Marker: "X-FORWARDED-FOR", Severity: proxy.SeverityMedium},
+		{Kind: proxy.FindingProxyHeader, Header: "Via", Value: "1.1 squid", Marker: "Via", Severity: proxy.SeverityMedium},
+	}, findings)
+
+	//every hostname is reported once
+	findings = rules.MatchHostnames([]string{"squid-cache.example.com.", "mail.example.com.", "proxy.example.com."})
+	if assert.Len(t, findings, 2) {
+		assert.Equal(t, "cache", findings[0].Marker)
+		assert.Equal(t, "squid-cache.example.com.", findings[0].Value)
+		assert.Equal(t, "proxy", findings[1].Marker)
+	}
+	assert.Empty(t, rules.MatchHostnames(nil))
+
+	assert.True(t, rules.Known("user-agent"))
+	assert.False(t, rules.Known("X-Custom"))
+}
+
+func TestNewRuleSet_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+	}{
+		{"missing header", Rules{Headers: []HeaderRule{{Implies: ImpliesProxyUsage}}}},
+		{"header and pattern", Rules{Headers: []HeaderRule{{Header: "Via", HeaderPattern: "via", Implies: ImpliesProxyUsage}}}},
+		{"invalid header pattern", Rules{Headers: []HeaderRule{{HeaderPattern: "(", Implies: ImpliesProxyUsage}}}},
+		{"invalid value pattern", Rules{Headers: []HeaderRule{{Header: "Via", ValuePattern: "[", Implies: ImpliesProxyUsage}}}},
+		{"unknown implication", Rules{Headers: []HeaderRule{{Header: "Via"}}}},
+		{"unknown severity", Rules{Headers: []HeaderRule{{Header: "Via", Implies: ImpliesProxyUsage, Severity: "fatal"}}}},
+		{"missing hostname pattern", Rules{Hostnames: []HostnameRule{{}}}},
+		{"hostname ip leak", Rules{Hostnames: []HostnameRule{{Pattern: "proxy", Implies: ImpliesIPLeak}}}},
+	}
+	for _, test := range tests {
+		_, err := NewRuleSet(test.rules)
+		assert.Error(t, err, test.name)
+	}
+}
+
+func TestRuleSet_MatchHeaders(t *testing.T) {
+	rules, err := NewRuleSet(Rules{Headers: []HeaderRule{
+		{HeaderPattern: `^x-(real|client)-ip$`, Implies: ImpliesIPLeak},
+		{HeaderPattern: `^x-`, ValuePattern: `^\d+\.\d+\.\d+\.\d+$`, Implies: ImpliesProxyUsage, Severity: proxy.SeverityLow},
+	}})
+	assert.NoError(t, err)
+	findings := rules.MatchHeaders(http.Header{
+		"X-Real-Ip":  {"1.2.3.4"},
+		"X-Trace-Id": {"abc"},
+	})
+	assert.Equal(t, []proxy.Finding{
+		{Kind: proxy.FindingRealIP, Header: "X-Real-Ip", Value: "1.2.3.4", Marker: `^x-(real|client)-ip$`, Severity: proxy.SeverityHigh},
+		{Kind: proxy.FindingProxyHeader, Header: "X-Real-Ip", Value: "1.2.3.4", Marker: `^x-`, Severity: proxy.SeverityLow},
+	}, findings)
+}
+
+func TestLoadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	yamlPath := filepath.Join(dir, "rules.yaml")
+	assert.NoError(t, ioutil.WriteFile(yamlPath, []byte(`
+headers:
+  - header: X-Leak
+    implies: ip_leak
+hostnames:
+  - pattern: "^vpn-"
+known_headers: [Accept]
+`), 0600))
+	rules, err := LoadRules(yamlPath)
+	if assert.NoError(t, err) {
+		assert.Len(t, rules.MatchHeaders(http.Header{"X-Leak": {"1"}}), 1)
+		assert.Len(t, rules.MatchHostnames([]string{"VPN-1.example.com."}), 1)
+		assert.True(t, rules.Known("Accept"))
+	}
+
+	jsonPath := filepath.Join(dir, "rules.json")
+	assert.NoError(t, ioutil.WriteFile(jsonPath, []byte(`{"headers":[{"header":"Via","implies":"proxy_usage"}]}`), 0600))
+	rules, err = LoadRules(jsonPath)
+	if assert.NoError(t, err) {
+		assert.Len(t, rules.MatchHeaders(http.Header{"Via": {"1.1 a"}}), 1)
+	}
+
+	//unknown fields are refused
+	assert.NoError(t, ioutil.WriteFile(yamlPath, []byte("headers:\n  - heder: Via\n"), 0600))
+	_, err = LoadRules(yamlPath)
+	assert.Error(t, err)
+	assert.NoError(t, ioutil.WriteFile(jsonPath, []byte(`{"hostname":[]}`), 0600))
+	_, err = LoadRules(jsonPath)
+	assert.Error(t, err)
+}
+
+func TestJudge_IPLeakRule(t *testing.T) {
+	j := newTestJudge(nil)
+	rules, err := NewRuleSet(Rules{Headers: []HeaderRule{{Header: "X-Origin-Token", Implies: ImpliesIPLeak}}})
+	assert.NoError(t, err)
+	j.SetRules(rules)
+
+	result := j.Analyze(newJudgeRequest("5.6.7.8:1234", "1.2.3.4", map[string]string{"X-Origin-Token": "abc"}))
+	assert.Equal(t, proxy.NewAnonymityLevel(true, false), result.AnonType)
+	assert.True(t, result.HasFinding(proxy.FindingRealIP))
+
+	//default rules are no longer used
+	result = j.Analyze(newJudgeRequest("5.6.7.8:1234", "1.2.3.4", map[string]string{"Via": "1.1 squid"}))
+	assert.Equal(t, proxy.NewAnonymityLevel(false, false), result.AnonType)
+}
+
+func TestJudge_WatchRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rules.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"headers":[{"header":"Via","implies":"proxy_usage"}]}`), 0600))
+
+	j := newTestJudge(nil)
+	assert.NoError(t, j.ReloadRules(path))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go j.WatchRules(ctx, path, 10*time.Millisecond)
+	//let the watcher record the current state of the file
+	time.Sleep(50 * time.Millisecond)
+
+	//invalid file keeps the current rules
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"headers":[{"header":"Via"}]}`), 0600))
+	assert.Error(t, j.ReloadRules(path))
+	assert.Len(t, j.Rules().MatchHeaders(http.Header{"Via": {"1.1 a"}}), 1)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"headers":[{"header":"X-Proxy","implies":"proxy_usage"}]}`), 0600))
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && len(j.Rules().MatchHeaders(http.Header{"X-Proxy": {"1"}})) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Len(t, j.Rules().MatchHeaders(http.Header{"X-Proxy": {"1"}}), 1)
+	assert.Empty(t, j.Rules().MatchHeaders(http.Header{"Via": {"1.1 a"}}))
+}
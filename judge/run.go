@@ -1,241 +1,255 @@
 package judge
 
 import (
-	"fmt"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
-	"net/textproto"
+	"net/url"
 	"strings"
-)
-
-var hostnameMarkers = []string{"cache",
-	"squid",
-	"proxy"}
-
-var proxyHeaderMarkers = []string{"Client-Ip",
-	"HTTP_CLIENT_IP",
-	"FORWARDED",
-	"FORWARDED-FOR",
-	"FORWARDED-FOR-IP",
-	"X-FORWARDED",
-	"X-FORWARDED-FOR",
-	"PROXY_CONNECTION",
-	"Via",
-	"X-Proxy-Id",
-	"X-Bluecoat-Via",
-	"X-Iwproxy",
-}
 
-func (j *Judge) Start() {
-	j.logger.Infof("Starting proxy judge v. %s", version)
+	"github.com/alekc/proxy"
+)
 
-	//load cf ranges
-	if j.CloudFlareSupport {
-		j.logger.Debug("Loading cf ip ranges")
-		loadCfRanges()
-		j.logger.Debug("Cf ranges loaded")
-	}
-	//listen
-	http.HandleFunc("/", j.analyzeRequest)
-	j.logger.Debugf("Listening on %s", j.ListenAddress)
-	err := http.ListenAndServe(j.ListenAddress, nil) // set listen port
-	if err != nil {
-		j.logger.WithError(err).Fatal("ListenAndServe fail")
-	}
-}
+//same limit as used by http.Request.ParseForm
+const maxFormSize = 10 << 20
 
 func (j *Judge) analyzeRequest(w http.ResponseWriter, req *http.Request) {
 	//Debug Block
 	j.logRequest(req)
 
-	//set up markers
-	showsRealIP := false
-	showsProxyUsage := false
+	result := j.Analyze(req)
+	j.logger.
+		WithField("judgement", result.AnonType.Description()).
+		WithField("anonymity", result.AnonType.String()).
+		Debug("judgement finished")
 
+	var encodedBody []byte
+	if j.AnonymityNames {
+		encodedBody, _ = result.MarshalJSONNames()
+	} else {
+		encodedBody, _ = result.MarshalJSON()
+	}
+	_, _ = w.Write(encodedBody)
+	j.logger.
+		WithField("body", string(encodedBody)).
+		Info("http response")
+}
+
+//Analyze judges the anonymity of the proxy which sent the request. The request is not modified
+//(the body of a form post is read and replaced with an identical copy), so Analyze can be used
+//outside of the judge server.
+func (j *Judge) Analyze(req *http.Request) *proxy.Judgement {
 	result := NewJudgement()
+	result.TLS = tlsInfo(req)
+	headers := copyHeader(req.Header)
 
-	//if cloudflare is supported get the country from header
-	if j.CloudFlareSupport {
-		result.Country = req.Header.Get("Cf-IpCountry")
+	//if the judge is behind an edge (i.e. cloudflare) get the country from header
+	edge := j.edge()
+	if edge != nil && edge.CountryHeader() != "" {
+		result.Country = headers.Get(edge.CountryHeader())
 	}
 
 	//getRealIPFromPost
 	result.RealIP = j.getRealIPFromPost(req)
-	result.RemoteIP = j.getRemoteIp(req)
+	//normalize forwarding headers removing edge servers and trusted gateways
+	trusted := j.trust()
+	peer := j.getPeerIp(req)
+	client, chains := j.normalizeForwarding(headers, peer, result.RealIP, trusted)
+	if len(chains) > 0 {
+		result.Forwarding = chains
+	}
+	result.RemoteIP = j.getRemoteIp(headers, peer, client, trusted)
+	//headers of the edge are only ignored if the request came through it, otherwise they were sent by the proxy
+	if trusted.contains(peer) {
+		removeEdgeHeaders(headers, edge)
+	}
 
-	//check reverse hostname of proxy ip for markers
-	if msg := j.CheckReverse(result.RemoteIP.String()); len(msg) > 0 {
-		showsProxyUsage = true
-		result.AppendMessages(msg)
+	//location and owner of the proxy, country set by the edge takes precedence
+	if j.GeoIP != nil && result.RemoteIP != nil {
+		record := j.GeoIP.Lookup(result.RemoteIP)
+		if result.Country == "" {
+			result.Country = record.Country
+		}
+		result.City, result.ASN, result.Org = record.City, record.ASN, record.Org
+	}
+	if j.Classifier != nil && result.RemoteIP != nil {
+		result.Categories = j.Classifier.Classify(result.RemoteIP)
 	}
 
-	//normalize xforwardedFor removing cloudflare and trusted gateways
-	j.normalizeXForwardedFor(req)
+	//identify proxy software
+	if via, ok := headers["Via"]; ok {
+		result.Via = ParseVia(via)
+	}
+	if software := j.Fingerprints.Identify(headers); len(software) > 0 {
+		result.ProxySoftware = software
+	}
+
+	//check reverse hostname of proxy ip for markers
+	if result.RemoteIP == nil {
+		j.logger.WithField("remote_addr", req.RemoteAddr).Warn("Couldn't parse remote ip")
+	} else {
+		result.AppendFindings(j.CheckReverse(result.RemoteIP.String()))
+	}
 
 	//search our ip in all headers
 	if result.RealIP != "" {
-		if msg := j.checkIPInHeaders(req, result.RealIP); len(msg) > 0 {
-			showsRealIP = true
-			result.AppendMessages(msg)
-		}
+		result.AppendFindings(j.checkIPInHeaders(headers, result.RealIP))
 	}
 
 	//check headers
-	if msg := j.hasProxyHeaderMarkers(req); len(msg) > 0 {
-		showsProxyUsage = true
-		result.AppendMessages(msg)
-	}
-
-	//final judgement
-	if showsRealIP {
-		if showsProxyUsage {
-			j.logger.
-				WithField("judgement", "shows real ip, shows proxy usage").
-				Debug("judgement finished")
-			result.AnonType = 0
-		} else {
-			j.logger.
-				WithField("judgement", "shows real ip, hides proxy usage").
-				Debug("judgement finished")
-			result.AnonType = 1
-		}
-	} else {
-		if showsProxyUsage {
-			j.logger.
-				WithField("judgement", "doesn't show real ip, shows proxy usage").
-				Debug("judgement finished")
-			result.AnonType = 2
-		} else {
-			j.logger.
-				WithField("judgement", "doesn't show real ip, doesn't show proxy usage").
-				Debug("judgement finished")
-			result.AnonType = 3
-		}
-	}
+	result.AppendFindings(j.hasProxyHeaderMarkers(headers))
 
-	encodedBody, _ := result.MarshalJSON()
-	_, _ = w.Write(encodedBody)
+	//final judgement, weighted findings are mapped to the anonymity level by the policy
+	policy := j.Rules().Policy()
+	result.Score = policy.Score(result.Findings)
+	result.AnonType = policy.Level(result.Score)
 	j.logger.
-		WithField("body", string(encodedBody)).
-		Info("http response")
+		WithField("ip_leak", result.Score.IPLeak).
+		WithField("proxy_usage", result.Score.ProxyUsage).
+		Debug("judgement scored")
+	return result
+}
+
+//copyHeader returns a deep copy of the header, so it can be normalized without touching the request
+func copyHeader(header http.Header) http.Header {
+	result := make(http.Header, len(header))
+	for k, v := range header {
+		result[k] = append([]string(nil), v...)
+	}
+	return result
 }
 
-func (j *Judge) checkIPInHeaders(req *http.Request, realIP string) []string {
-	msg := make([]string, 0)
-	for k, v := range req.Header {
-		if !strings.Contains(strings.Join(v, ","), realIP) {
+//checkIPInHeaders looks for the real ip in all headers, including its encoded forms (see leakDetector)
+func (j *Judge) checkIPInHeaders(headers http.Header, realIP string) []proxy.Finding {
+	findings := make([]proxy.Finding, 0)
+	detector := newLeakDetector(realIP)
+	for k, v := range headers {
+		value := strings.Join(v, ",")
+		token, encoding, ok := detector.find(value)
+		if !ok {
 			continue
 		}
 		//found our ip in the header
 		j.logger.
 			WithField("header_name", k).
 			WithField("header_value", v).
+			WithField("encoding", encoding).
 			Infof("Found real ip in headers")
-		msg = append(msg, fmt.Sprintf("Found real ip in the header [%s]", k))
+		findings = append(findings, proxy.Finding{
+			Kind:     proxy.FindingRealIP,
+			Header:   k,
+			Value:    value,
+			Marker:   token,
+			Severity: proxy.SeverityHigh,
+			Encoding: encoding,
+		})
 	}
-	return msg
+	return findings
+}
+
+//Gets real ip from the posted form (or the query string). The request body is restored after reading.
+func (j *Judge) getRealIPFromPost(req *http.Request) string {
+	form, err := readForm(req)
+	if err != nil {
+		j.logger.WithError(err).Warn("Couldn't get real ip")
+		return ""
+	}
+	realIP := strings.TrimSpace(form.Get("real-ip"))
+	//normalize ip representation (mostly relevant for ipv6 which can be written in multiple forms)
+	if ip := net.ParseIP(realIP); ip != nil {
+		realIP = ip.String()
+	}
+	return realIP
 }
 
-//Normalize X-Forwarded-For header based on cloudflare support and trusted gateways
-func (j *Judge) normalizeXForwardedFor(req *http.Request) {
-	forwardedFor := make([]string, 0)
-
-	//loop through ip and remove those which are acceptable
-	headerSlices := req.Header[textproto.CanonicalMIMEHeaderKey("X-Forwarded-For")]
-	for _, headerValue := range headerSlices {
-		for _, tempIP := range strings.Split(headerValue, ",") { //in case we have multiple entries
-			//if cloudflare support is enabled, check if ip belongs to its network
-			if j.CloudFlareSupport && ipBelongsToCfNetwork(net.ParseIP(tempIP)) {
-				continue
+//readForm returns post and query values like http.Request.ParseForm, without populating the request fields
+func readForm(req *http.Request) (url.Values, error) {
+	if req.Form != nil {
+		return req.Form, nil
+	}
+	form := make(url.Values)
+	if req.Body != nil && (req.Method == http.MethodPost || req.Method == http.MethodPut || req.Method == http.MethodPatch) {
+		contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if contentType == "application/x-www-form-urlencoded" {
+			body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxFormSize+1))
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			if err != nil {
+				return nil, err
 			}
-			//check if ip is in the range of trusted gateways.
-			found := false
-			for _, trustedIP := range j.TrustedGatewaysIps {
-				if tempIP == trustedIP {
-					found = true
-					break
-				}
+			if len(body) > maxFormSize {
+				return nil, errors.New("http: POST too large")
 			}
-			if !found {
-				forwardedFor = append(forwardedFor, tempIP)
+			if form, err = url.ParseQuery(string(body)); err != nil {
+				return nil, err
 			}
 		}
 	}
-	//if forwardedFor is empty we can safely remove that header from our search
-	//it would mean that proxy has not added any new ip
-	if len(forwardedFor) == 0 {
-		req.Header.Del("x-forwarded-for")
+	if req.URL != nil {
+		query, err := url.ParseQuery(req.URL.RawQuery)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range query {
+			form[k] = append(form[k], v...)
+		}
 	}
+	return form, nil
 }
 
-//Gets real ip
-func (j *Judge) getRealIPFromPost(req *http.Request) string {
-	realIP := ""
-	if err := req.ParseForm(); err == nil {
-		realIP = req.Form.Get("real-ip")
-	} else {
-		j.logger.WithError(err).Warn("Couldn't get real ip")
+//Gets ip of the peer which connected to the judge
+func (j *Judge) getPeerIp(req *http.Request) net.IP {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		ip = req.RemoteAddr
 	}
-	return realIP
+	return net.ParseIP(ip)
 }
 
-//
-func (j *Judge) getRemoteIp(req *http.Request) net.IP {
-	//get Remote ip. Replace it with cloudflare value if needed
-	ip, _, _ := net.SplitHostPort(req.RemoteAddr)
-	remoteIp := net.ParseIP(ip)
-
-	//If cloudflare support is enabled, then replace remote ip with
-	//contents of CF-Connecting-Ip header
-	//Also add current remote ip to the array of ips which have to be removed from
-	//forwarded for header
-	if j.CloudFlareSupport {
-		if ip = req.Header.Get("CF-Connecting-IP"); ip != "" {
-			temp := net.ParseIP(ip)
-			if temp != nil {
-				remoteIp = temp
-			}
-		}
+//Gets remote ip (the proxy) from the client derived from the forwarding chain.
+//Replace it with the client ip header of the edge (i.e. CF-Connecting-Ip) if the request came through the edge
+func (j *Judge) getRemoteIp(headers http.Header, peer, client net.IP, trusted *trust) net.IP {
+	if trusted.edge == nil || trusted.edge.ClientIPHeader() == "" || !trusted.contains(peer) {
+		return client
 	}
-	return remoteIp
+	if temp := net.ParseIP(stripPort(strings.TrimSpace(headers.Get(trusted.edge.ClientIPHeader())))); temp != nil {
+		return temp
+	}
+	return client
 }
 
-//checks if headers have certain markers, i.e. FORWARDED-FOR
-func (j *Judge) hasProxyHeaderMarkers(req *http.Request) []string {
-	msg := make([]string, 0)
-	for _, marker := range proxyHeaderMarkers {
-		key := textproto.CanonicalMIMEHeaderKey(marker)
-		if val, ok := req.Header[key]; ok {
-			j.logger.
-				WithField("header_name", marker).
-				WithField("header_value", strings.Join(val, ",")).
-				Debug("Header marker found")
-			msg = append(msg, fmt.Sprintf("Header [%s] is present", marker))
-		}
+//checks if headers match the header rules, i.e. FORWARDED-FOR
+func (j *Judge) hasProxyHeaderMarkers(headers http.Header) []proxy.Finding {
+	findings := j.Rules().MatchHeaders(headers)
+	for _, finding := range findings {
+		j.logger.
+			WithField("header_name", finding.Header).
+			WithField("header_value", finding.Value).
+			WithField("marker", finding.Marker).
+			Debug("Header marker found")
 	}
-	return msg
+	return findings
 }
 
-//Checks if name contain certain markers
-func (j *Judge) CheckReverse(ip string) []string {
-	res := make([]string, 0)
-	names, err := net.LookupAddr(ip)
+//Checks if name matches the hostname rules
+func (j *Judge) CheckReverse(ip string) []proxy.Finding {
+	names, err := j.LookupAddr(ip)
 	if err != nil {
 		j.logger.
 			WithError(err).
 			Error("error on ip reversal")
-		return res
+		return make([]proxy.Finding, 0)
 	}
 	//look for patterns
-	fullNames := strings.Join(names, ",")
-	for _, mark := range hostnameMarkers {
-		if strings.Contains(fullNames, mark) {
-			j.logger.
-				WithField("mark", mark).
-				WithField("resolved_hostname", fullNames).
-				Info("Found host marker")
-			res = append(res, fmt.Sprintf("Hostname contains %s", mark))
-		}
+	findings := j.Rules().MatchHostnames(names)
+	for _, finding := range findings {
+		j.logger.
+			WithField("mark", finding.Marker).
+			WithField("resolved_hostname", finding.Value).
+			Info("Found host marker")
 	}
-	return res
+	return findings
 }
@@ -0,0 +1,254 @@
+package judge
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alekc/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+//newTestJudge returns a judge which doesn't use the network for reverse lookups
+func newTestJudge(hostnames map[string][]string) *Judge {
+	j := Create()
+	j.CloudFlareSupport = false
+	j.LookupAddr = func(ip string) ([]string, error) {
+		return hostnames[ip], nil
+	}
+	return j
+}
+
+//newJudgeRequest creates a request sent by the proxy at remoteAddr reporting the real ip in the form
+func newJudgeRequest(remoteAddr, realIP string, headers map[string]string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("real-ip="+realIP))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return req
+}
+
+func TestJudge_Analyze(t *testing.T) {
+	tests := []struct {
+		name       string
+		cloudflare bool
+		gateways   []string
+		hops       int
+		hostnames  map[string][]string
+		remoteAddr string
+		headers    map[string]string
+		anonymity  proxy.AnonymityLevel
+		remoteIP   string
+		country    string
+		findings   []proxy.FindingKind
+	}{
+		{
+			name:       "elite",
+			remoteAddr: "5.6.7.8:4000",
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "5.6.7.8",
+		},
+		{
+			name:       "anonymous via header",
+			remoteAddr: "5.6.7.8:4000",
+			headers:    map[string]string{"Via": "1.1 squid"},
+			anonymity:  proxy.AnonAnonymous,
+			remoteIP:   "5.6.7.8",
+			findings:   []proxy.FindingKind{proxy.FindingProxyHeader},
+		},
+		{
+			name:       "weak hostname marker",
+			remoteAddr: "5.6.7.8:4000",
+			hostnames:  map[string][]string{"5.6.7.8": {"proxy-5.example.com."}},
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "5.6.7.8",
+			findings:   []proxy.FindingKind{proxy.FindingHostname},
+		},
+		{
+			name:       "several markers in a hostname count once",
+			remoteAddr: "5.6.7.8:4000",
+			hostnames:  map[string][]string{"5.6.7.8": {"squid-proxy-5.example.com."}},
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "5.6.7.8",
+			findings:   []proxy.FindingKind{proxy.FindingHostname},
+		},
+		{
+			name:       "anonymous hostname",
+			remoteAddr: "5.6.7.8:4000",
+			hostnames:  map[string][]string{"5.6.7.8": {"squid-5.example.com.", "proxy-5.example.com."}},
+			anonymity:  proxy.AnonAnonymous,
+			remoteIP:   "5.6.7.8",
+			findings:   []proxy.FindingKind{proxy.FindingHostname, proxy.FindingHostname},
+		},
+		{
+			name:       "hidden proxy",
+			remoteAddr: "5.6.7.8:4000",
+			headers:    map[string]string{"X-Client": "1.2.3.4"},
+			anonymity:  proxy.AnonHiddenProxy,
+			remoteIP:   "5.6.7.8",
+			findings:   []proxy.FindingKind{proxy.FindingRealIP},
+		},
+		{
+			name:       "transparent",
+			remoteAddr: "5.6.7.8:4000",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4"},
+			anonymity:  proxy.AnonTransparent,
+			remoteIP:   "5.6.7.8",
+			findings:   []proxy.FindingKind{proxy.FindingRealIP, proxy.FindingProxyHeader},
+		},
+		{
+			name:       "cloudflare",
+			cloudflare: true,
+			remoteAddr: "173.245.48.1:4000",
+			headers: map[string]string{
+				"X-Forwarded-For":  "173.245.48.5",
+				"Cf-Connecting-Ip": "5.6.7.8",
+				"Cf-Ipcountry":     "DE",
+			},
+			anonymity: proxy.AnonElite,
+			remoteIP:  "5.6.7.8",
+			country:   "DE",
+		},
+		{
+			name:       "cloudflare headers without cloudflare support",
+			remoteAddr: "173.245.48.1:4000",
+			headers: map[string]string{
+				"X-Forwarded-For":  "173.245.48.5",
+				"Cf-Connecting-Ip": "5.6.7.8",
+				"Cf-Ipcountry":     "DE",
+			},
+			anonymity: proxy.AnonAnonymous,
+			remoteIP:  "173.245.48.1",
+			findings:  []proxy.FindingKind{proxy.FindingProxyHeader},
+		},
+		{
+			name:       "cloudflare transparent",
+			cloudflare: true,
+			remoteAddr: "173.245.48.1:4000",
+			headers: map[string]string{
+				"X-Forwarded-For":  "1.2.3.4, 5.6.7.8",
+				"Cf-Connecting-Ip": "5.6.7.8",
+			},
+			anonymity: proxy.AnonTransparent,
+			remoteIP:  "5.6.7.8",
+			findings:  []proxy.FindingKind{proxy.FindingRealIP, proxy.FindingProxyHeader},
+		},
+		{
+			name:       "trusted gateway",
+			gateways:   []string{"10.0.0.1"},
+			remoteAddr: "10.0.0.1:4000",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.1"},
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "10.0.0.1",
+		},
+		{
+			name:       "untrusted gateway",
+			remoteAddr: "10.0.0.1:4000",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.1"},
+			anonymity:  proxy.AnonAnonymous,
+			remoteIP:   "10.0.0.1",
+			findings:   []proxy.FindingKind{proxy.FindingProxyHeader},
+		},
+		{
+			name:       "trusted gateway subnet",
+			gateways:   []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:4000",
+			headers:    map[string]string{"X-Forwarded-For": "5.6.7.8:3128 , 10.0.0.7"},
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "5.6.7.8",
+		},
+		{
+			name:       "transparent behind trusted gateway",
+			gateways:   []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:4000",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 5.6.7.8"},
+			anonymity:  proxy.AnonTransparent,
+			remoteIP:   "5.6.7.8",
+			findings:   []proxy.FindingKind{proxy.FindingRealIP, proxy.FindingProxyHeader},
+		},
+		{
+			name:       "spoofed gateway",
+			gateways:   []string{"10.0.0.0/8"},
+			remoteAddr: "5.6.7.8:4000",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.1"},
+			anonymity:  proxy.AnonAnonymous,
+			remoteIP:   "5.6.7.8",
+			findings:   []proxy.FindingKind{proxy.FindingProxyHeader},
+		},
+		{
+			name:       "trusted hops",
+			hops:       2,
+			remoteAddr: "192.0.2.1:4000",
+			headers:    map[string]string{"X-Forwarded-For": "5.6.7.8, 192.0.2.99"},
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "5.6.7.8",
+		},
+		{
+			name:       "forwarded header",
+			gateways:   []string{"2001:db8::/32"},
+			remoteAddr: "[2001:db8::5]:4000",
+			headers:    map[string]string{"Forwarded": `for=5.6.7.8;proto=http, For="[2001:db8::7]:443"`},
+			anonymity:  proxy.AnonElite,
+			remoteIP:   "5.6.7.8",
+		},
+		{
+			name:       "forwarded header transparent",
+			gateways:   []string{"10.0.0.1"},
+			remoteAddr: "10.0.0.1:4000",
+			headers:    map[string]string{"Forwarded": "for=1.2.3.4;by=5.6.7.8, for=5.6.7.8"},
+			anonymity:  proxy.AnonTransparent,
+			remoteIP:   "5.6.7.8",
+			findings:   []proxy.FindingKind{proxy.FindingRealIP, proxy.FindingProxyHeader},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			j := newTestJudge(test.hostnames)
+			j.CloudFlareSupport = test.cloudflare
+			assert.NoError(t, j.SetTrustedGateways(test.gateways))
+			j.TrustedHops = test.hops
+
+			result := j.Analyze(newJudgeRequest(test.remoteAddr, "1.2.3.4", test.headers))
+			assert.Equal(t, test.anonymity, result.AnonType)
+			assert.Equal(t, "1.2.3.4", result.RealIP)
+			assert.Equal(t, test.remoteIP, result.RemoteIP.String())
+			assert.Equal(t, test.country, result.Country)
+			for _, kind := range test.findings {
+				assert.True(t, result.HasFinding(kind), "missing finding %s", kind)
+			}
+			if len(test.findings) == 0 {
+				assert.Empty(t, result.Findings)
+			}
+		})
+	}
+}
+
+func TestJudge_AnalyzeDoesNotModifyRequest(t *testing.T) {
+	j := newTestJudge(nil)
+	j.CloudFlareSupport = true
+	req := newJudgeRequest("173.245.48.1:4000", "1.2.3.4", map[string]string{"X-Forwarded-For": "173.245.48.5"})
+
+	first := j.Analyze(req)
+	second := j.Analyze(req)
+	assert.Equal(t, first, second)
+
+	//cloudflare entries are removed only from the copy of the headers
+	assert.Equal(t, "173.245.48.5", req.Header.Get("X-Forwarded-For"))
+	assert.Nil(t, req.Form)
+	body, _ := ioutil.ReadAll(req.Body)
+	assert.Equal(t, "real-ip=1.2.3.4", string(body))
+}
+
+func TestJudge_AnalyzeRealIPFromQuery(t *testing.T) {
+	j := newTestJudge(nil)
+	req := httptest.NewRequest(http.MethodGet, "/?real-ip=2001:db8:0::1", nil)
+	req.Header.Set("X-Real-Ip", "2001:db8::1")
+
+	result := j.Analyze(req)
+	assert.Equal(t, "2001:db8::1", result.RealIP)
+	assert.Equal(t, proxy.AnonHiddenProxy, result.AnonType)
+}
@@ -0,0 +1,115 @@
+package judge
+
+import (
+	"fmt"
+
+	"github.com/alekc/proxy"
+)
+
+//ScoringPolicy maps weighted findings to the anonymity level. Findings of the real ip add to the
+//ip_leak score, all other findings to the proxy_usage score. The real ip (proxy usage) is considered
+//visible if its score reaches the threshold.
+type ScoringPolicy struct {
+	//Weights of the severities, used for findings without their own weight
+	Weights map[proxy.Severity]float64 `json:"weights,omitempty" yaml:"weights,omitempty"`
+	//Minimal ip_leak score showing the real ip, nil means the default
+	IPLeakThreshold *float64 `json:"ip_leak_threshold,omitempty" yaml:"ip_leak_threshold,omitempty"`
+	//Minimal proxy_usage score showing the proxy usage, nil means the default
+	ProxyUsageThreshold *float64 `json:"proxy_usage_threshold,omitempty" yaml:"proxy_usage_threshold,omitempty"`
+}
+
+//Default thresholds of the scores
+const (
+	defaultIPLeakThreshold     = 4
+	defaultProxyUsageThreshold = 2
+)
+
+//DefaultScoringPolicy returns the built in policy. A single low severity finding (i.e. hostname
+//marker) isn't enough to show the proxy usage, any medium or high severity finding is.
+func DefaultScoringPolicy() ScoringPolicy {
+	return ScoringPolicy{
+		Weights: map[proxy.Severity]float64{
+			proxy.SeverityLow:    1,
+			proxy.SeverityMedium: 2,
+			proxy.SeverityHigh:   4,
+		},
+		IPLeakThreshold:     float(defaultIPLeakThreshold),
+		ProxyUsageThreshold: float(defaultProxyUsageThreshold),
+	}
+}
+
+//float returns pointer to the value, used for optional weights and thresholds
+func float(value float64) *float64 {
+	return &value
+}
+
+//withDefaults validates the policy and fills unset values from DefaultScoringPolicy
+func (p ScoringPolicy) withDefaults() (ScoringPolicy, error) {
+	def := DefaultScoringPolicy()
+	weights := def.Weights
+	for severity, weight := range p.Weights {
+		if !validSeverity(severity) {
+			return p, fmt.Errorf("scoring: unknown severity %q", severity)
+		}
+		if weight < 0 {
+			return p, fmt.Errorf("scoring: negative weight of %s", severity)
+		}
+		weights[severity] = weight
+	}
+	p.Weights = weights
+	ipLeak, proxyUsage := p.thresholds()
+	if ipLeak < 0 || proxyUsage < 0 {
+		return p, fmt.Errorf("scoring: negative threshold")
+	}
+	p.IPLeakThreshold, p.ProxyUsageThreshold = float(ipLeak), float(proxyUsage)
+	return p, nil
+}
+
+//thresholds returns the thresholds, unset thresholds use the defaults
+func (p *ScoringPolicy) thresholds() (ipLeak, proxyUsage float64) {
+	ipLeak, proxyUsage = defaultIPLeakThreshold, defaultProxyUsageThreshold
+	if p.IPLeakThreshold != nil {
+		ipLeak = *p.IPLeakThreshold
+	}
+	if p.ProxyUsageThreshold != nil {
+		proxyUsage = *p.ProxyUsageThreshold
+	}
+	return ipLeak, proxyUsage
+}
+
+//weight returns the weight if set, the weight of the severity otherwise
+func (p *ScoringPolicy) weight(severity proxy.Severity, weight *float64) float64 {
+	if weight != nil {
+		return *weight
+	}
+	return p.Weights[severity]
+}
+
+//Score sums the weights of the findings
+func (p *ScoringPolicy) Score(findings []proxy.Finding) *proxy.Score {
+	score := &proxy.Score{Contributions: make([]proxy.Contribution, 0, len(findings))}
+	for _, finding := range findings {
+		weight := p.weight(finding.Severity, finding.Weight)
+		contribution := proxy.Contribution{
+			Kind:    finding.Kind,
+			Header:  finding.Header,
+			Marker:  finding.Marker,
+			Implies: string(ImpliesProxyUsage),
+			Weight:  weight,
+		}
+		if finding.Kind == proxy.FindingRealIP {
+			contribution.Implies = string(ImpliesIPLeak)
+			score.IPLeak += weight
+		} else {
+			score.ProxyUsage += weight
+		}
+		score.Contributions = append(score.Contributions, contribution)
+	}
+	return score
+}
+
+//Level returns the anonymity level of the score. Unset thresholds use the defaults.
+func (p *ScoringPolicy) Level(score *proxy.Score) proxy.AnonymityLevel {
+	ipLeak, proxyUsage := p.thresholds()
+	return proxy.NewAnonymityLevel(score.IPLeak >= ipLeak, score.ProxyUsage >= proxyUsage)
+}
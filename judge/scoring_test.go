@@ -0,0 +1,107 @@
+package judge
+
+import (
+	"testing"
+
+	"github.com/alekc/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoringPolicy_Score(t *testing.T) {
+	policy := DefaultScoringPolicy()
+	score := policy.Score([]proxy.Finding{
+		{Kind: proxy.FindingRealIP, Header: "X-Forwarded-For", Marker: "1.2.3.4", Severity: proxy.SeverityHigh},
+		{Kind: proxy.FindingProxyHeader, Header: "Via", Marker: "Via", Severity: proxy.SeverityMedium, Weight: float(0.5)},
+		{Kind: proxy.FindingHostname, Marker: "cache", Severity: proxy.SeverityLow},
+	})
+	assert.Equal(t, &proxy.Score{
+		IPLeak:     4,
+		ProxyUsage: 1.5,
+		Contributions: []proxy.Contribution{
+			{Kind: proxy.FindingRealIP, Header: "X-Forwarded-For", Marker: "1.2.3.4", Implies: "ip_leak", Weight: 4},
+			{Kind: proxy.FindingProxyHeader, Header: "Via", Marker: "Via", Implies: "proxy_usage", Weight: 0.5},
+			{Kind: proxy.FindingHostname, Marker: "cache", Implies: "proxy_usage", Weight: 1},
+		},
+	}, score)
+	assert.Equal(t, proxy.AnonHiddenProxy, policy.Level(score))
+
+	assert.Equal(t, proxy.AnonElite, policy.Level(policy.Score(nil)))
+	assert.Equal(t, proxy.AnonTransparent, policy.Level(&proxy.Score{IPLeak: 4, ProxyUsage: 2}))
+	assert.Equal(t, proxy.AnonAnonymous, policy.Level(&proxy.Score{IPLeak: 3.9, ProxyUsage: 2}))
+}
+
+func TestNewRuleSet_Scoring(t *testing.T) {
+	rules, err := NewRuleSet(Rules{Scoring: &ScoringPolicy{
+		Weights:             map[proxy.Severity]float64{proxy.SeverityLow: 3},
+		ProxyUsageThreshold: float(3),
+	}})
+	if assert.NoError(t, err) {
+		policy := rules.Policy()
+		assert.Equal(t, 3.0, policy.Weights[proxy.SeverityLow])
+		assert.Equal(t, 2.0, policy.Weights[proxy.SeverityMedium], "unset weights should use defaults")
+		assert.Equal(t, 3.0, *policy.ProxyUsageThreshold)
+		assert.Equal(t, 4.0, *policy.IPLeakThreshold)
+	}
+
+	invalid := []*ScoringPolicy{
+		{Weights: map[proxy.Severity]float64{"fatal": 1}},
+		{Weights: map[proxy.Severity]float64{proxy.SeverityHigh: -1}},
+		{IPLeakThreshold: float(-1)},
+	}
+	for _, policy := range invalid {
+		_, err := NewRuleSet(Rules{Scoring: policy})
+		assert.Error(t, err)
+	}
+	_, err = NewRuleSet(Rules{Hostnames: []HostnameRule{{Pattern: "vpn", Weight: float(-1)}}})
+	assert.Error(t, err)
+
+	//zero weights and thresholds can be configured
+	rules, err = NewRuleSet(Rules{
+		Hostnames: []HostnameRule{{Pattern: "cache", Weight: float(0)}},
+		Scoring:   &ScoringPolicy{Weights: map[proxy.Severity]float64{proxy.SeverityMedium: 0}, IPLeakThreshold: float(0)},
+	})
+	if assert.NoError(t, err) {
+		policy := rules.Policy()
+		assert.Equal(t, 0.0, policy.Weights[proxy.SeverityMedium])
+		assert.Equal(t, 0.0, *policy.IPLeakThreshold)
+		findings := rules.MatchHostnames([]string{"cache.example.com."})
+		assert.Equal(t, 0.0, policy.Score(findings).ProxyUsage)
+		assert.Equal(t, proxy.AnonHiddenProxy, policy.Level(policy.Score(findings)), "zero threshold is always reached")
+	}
+}
+
+func TestRuleSet_MatchHostnamesMaxWeight(t *testing.T) {
+	rules, err := NewRuleSet(Rules{Hostnames: []HostnameRule{
+		{Pattern: "proxy"},
+		{Pattern: "squid", Severity: proxy.SeverityHigh},
+		{Pattern: "example", Weight: float(0.5)},
+	}})
+	assert.NoError(t, err)
+	findings := rules.MatchHostnames([]string{"squid-proxy-5.example.com."})
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, "squid", findings[0].Marker)
+	}
+	assert.Equal(t, 4.0, rules.Policy().Score(findings).ProxyUsage)
+}
+
+func TestJudge_AnalyzeScore(t *testing.T) {
+	j := newTestJudge(map[string][]string{"5.6.7.8": {"cache.example.com."}})
+	rules, err := NewRuleSet(Rules{
+		Headers:   []HeaderRule{{Header: "X-Proxy-Id", Implies: ImpliesProxyUsage, Weight: float(0.5)}},
+		Hostnames: []HostnameRule{{Pattern: "cache", Weight: float(1.5)}},
+	})
+	assert.NoError(t, err)
+	j.SetRules(rules)
+
+	//neither signal is enough alone
+	result := j.Analyze(newJudgeRequest("5.6.7.8:4000", "1.2.3.4", map[string]string{"X-Proxy-Id": "1"}))
+	assert.Equal(t, proxy.AnonAnonymous, result.AnonType)
+	if assert.NotNil(t, result.Score) {
+		assert.Equal(t, 2.0, result.Score.ProxyUsage)
+		assert.Len(t, result.Score.Contributions, len(result.Findings))
+	}
+
+	result = j.Analyze(newJudgeRequest("9.9.9.9:4000", "1.2.3.4", map[string]string{"X-Proxy-Id": "1"}))
+	assert.Equal(t, proxy.AnonElite, result.AnonType)
+	assert.Equal(t, 0.5, result.Score.ProxyUsage)
+}
@@ -0,0 +1,139 @@
+package judge
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+)
+
+//ErrServerRunning is returned when Serve is called on a judge which is already serving
+var ErrServerRunning = errors.New("judge is already running")
+
+//ServeHTTP implements http.Handler, so the judge can be mounted in any server or mux
+func (j *Judge) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	j.analyzeRequest(w, req)
+}
+
+//Start listens on ListenAddress and serves requests until Shutdown is called
+func (j *Judge) Start() error {
+	return j.ListenAndServe(context.Background())
+}
+
+//ListenAndServe listens on ListenAddress and serves requests until the context is cancelled
+//or Shutdown is called.
+func (j *Judge) ListenAndServe(ctx context.Context) error {
+	listener, err := net.Listen("tcp", j.ListenAddress)
+	if err != nil {
+		return err
+	}
+	return j.Serve(ctx, listener)
+}
+
+//Serve accepts connections on the listener until the context is cancelled or Shutdown is called.
+//When the context is cancelled the server is shut down gracefully, waiting up to ShutdownTimeout
+//for active requests. Returns nil after a graceful shutdown.
+func (j *Judge) Serve(ctx context.Context, listener net.Listener) error {
+	return j.serve(ctx, listener, nil)
+}
+
+//serve serves plain http or, if tlsConfig is set, https
+func (j *Judge) serve(ctx context.Context, listener net.Listener, tlsConfig *tls.Config) error {
+	j.logger.Infof("Starting proxy judge v. %s", version)
+	if err := j.loadTrustedGateways(j.TrustedGatewaysIps); err != nil {
+		_ = listener.Close()
+		return err
+	}
+
+	//load and refresh edge ip ranges while serving
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if loader, ok := j.edge().(rangeLoader); ok {
+		j.logger.Debug("Loading edge ip ranges")
+		if err := loader.Load(ctx); err == nil {
+			j.logger.Debug("Edge ranges loaded")
+		}
+		go loader.Run(ctx)
+	}
+
+	srv := &http.Server{
+		Handler:           j,
+		ReadTimeout:       j.ReadTimeout,
+		ReadHeaderTimeout: j.ReadHeaderTimeout,
+		WriteTimeout:      j.WriteTimeout,
+		IdleTimeout:       j.IdleTimeout,
+		MaxHeaderBytes:    j.MaxHeaderBytes,
+	}
+	if tlsConfig != nil {
+		srv.TLSConfig = tlsConfig.Clone()
+	}
+	j.mu.Lock()
+	if j.server != nil {
+		j.mu.Unlock()
+		_ = listener.Close()
+		return ErrServerRunning
+	}
+	stopped := make(chan struct{})
+	j.server = srv
+	j.stopped = stopped
+	j.mu.Unlock()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), j.ShutdownTimeout)
+			defer cancel()
+			if err := j.Shutdown(shutdownCtx); err != nil {
+				j.logger.WithError(err).Error("Graceful shutdown failed")
+			}
+		case <-done:
+		}
+	}()
+
+	j.logger.Debugf("Listening on %s", listener.Addr())
+	var err error
+	if tlsConfig != nil {
+		err = srv.ServeTLS(listener, "", "")
+	} else {
+		err = srv.Serve(listener)
+	}
+	if err != http.ErrServerClosed {
+		j.release(srv)
+		return err
+	}
+	//Serve returns immediately after Shutdown is called, wait for active requests
+	<-stopped
+	return nil
+}
+
+//Shutdown stops the server gracefully. Active requests are given time until the context
+//is done, after that remaining connections are closed.
+func (j *Judge) Shutdown(ctx context.Context) error {
+	j.mu.Lock()
+	srv := j.server
+	j.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+
+	err := srv.Shutdown(ctx)
+	if err != nil {
+		_ = srv.Close()
+	}
+	j.release(srv)
+	j.logger.Info("Proxy judge stopped")
+	return err
+}
+
+//release forgets the server so the judge can be started again
+func (j *Judge) release(srv *http.Server) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.server == srv {
+		j.server = nil
+		close(j.stopped)
+	}
+}
@@ -0,0 +1,129 @@
+package judge
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJudge_ServeHTTP(t *testing.T) {
+	j := Create()
+	j.CloudFlareSupport = false
+
+	//judge can be mounted in any mux
+	mux := http.NewServeMux()
+	mux.Handle("/judge", j)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/judge")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	judgement := &proxy.Judgement{}
+	assert.NoError(t, judgement.UnmarshalJSON(body))
+	assert.Equal(t, "127.0.0.1", judgement.RemoteIP.String())
+	assert.Contains(t, string(body), `"anon_type":3`)
+
+	j.AnonymityNames = true
+	resp, err = http.Get(srv.URL + "/judge")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `"anon_type":"elite"`)
+}
+
+func TestJudge_ServeAndShutdown(t *testing.T) {
+	j := Create()
+	j.CloudFlareSupport = false
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- j.Serve(ctx, listener)
+	}()
+
+	resp, err := http.Post("http://"+listener.Addr().String(), "application/x-www-form-urlencoded", strings.NewReader("ip=1.2.3.4"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+	}
+
+	//second Serve on the same judge is refused
+	other, _ := net.Listen("tcp", "127.0.0.1:0")
+	assert.Equal(t, ErrServerRunning, j.Serve(context.Background(), other))
+
+	cancel()
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("judge wasn't stopped")
+	}
+
+	//judge can be started again after shutdown
+	listener, _ = net.Listen("tcp", "127.0.0.1:0")
+	go func() {
+		stopped <- j.Serve(context.Background(), listener)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("judge wasn't restarted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NoError(t, j.Shutdown(context.Background()))
+	assert.NoError(t, <-stopped)
+}
+
+func TestJudge_MaxHeaderBytes(t *testing.T) {
+	j := Create()
+	j.CloudFlareSupport = false
+	j.MaxHeaderBytes = 1024
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	go func() {
+		_ = j.Serve(context.Background(), listener)
+	}()
+	defer j.Shutdown(context.Background())
+
+	req, _ := http.NewRequest(http.MethodGet, "http://"+listener.Addr().String(), nil)
+	req.Header.Set("X-Large", strings.Repeat("a", 8*1024))
+	resp, err := http.DefaultClient.Do(req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, resp.StatusCode)
+		resp.Body.Close()
+	}
+}
+
+func TestJudge_ServeInvalidGateway(t *testing.T) {
+	j := Create()
+	j.CloudFlareSupport = false
+	j.TrustedGatewaysIps = []string{"10.0.0.1", "invalid"}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualError(t, j.Serve(context.Background(), listener), "invalid trusted gateway: invalid ip address invalid")
+}
@@ -0,0 +1,156 @@
+package judge
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alekc/proxy"
+)
+
+//CertificateLoader keeps a certificate loaded from the cert/key files. Reload can be called at any
+//time (i.e. on SIGHUP) to pick up renewed certificates without restarting the judge.
+type CertificateLoader struct {
+	CertFile string
+	KeyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+//NewCertificateLoader loads the certificate from the files
+func NewCertificateLoader(certFile, keyFile string) (*CertificateLoader, error) {
+	loader := &CertificateLoader{CertFile: certFile, KeyFile: keyFile}
+	if err := loader.Reload(); err != nil {
+		return nil, err
+	}
+	return loader, nil
+}
+
+//Reload reads the cert/key files again. Previous certificate stays in use if they can't be loaded.
+func (l *CertificateLoader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(l.CertFile, l.KeyFile)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.cert = &cert
+	l.mu.Unlock()
+	return nil
+}
+
+//GetCertificate can be used as tls.Config.GetCertificate
+func (l *CertificateLoader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.cert == nil {
+		return nil, errors.New("certificate is not loaded")
+	}
+	return l.cert, nil
+}
+
+//TLSConfig returns tls configuration serving the loaded certificate
+func (l *CertificateLoader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: l.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+//SelfSignedCertificate generates a certificate valid for a year for the given hosts (names or ips).
+//Meant for labs and tests, clients have to skip the verification.
+func SelfSignedCertificate(hosts ...string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Proxy Judge"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if host != "" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+//ListenAndServeTLS listens on ListenAddress and serves requests over tls until the context is
+//cancelled or Shutdown is called. Config has to provide a certificate.
+func (j *Judge) ListenAndServeTLS(ctx context.Context, config *tls.Config) error {
+	listener, err := net.Listen("tcp", j.ListenAddress)
+	if err != nil {
+		return err
+	}
+	return j.ServeTLS(ctx, listener, config)
+}
+
+//ServeTLS is like Serve but accepts tls connections. Config has to provide a certificate
+//(Certificates or GetCertificate), http/2 is enabled unless NextProtos are set.
+//Versions older than TLS 1.2 are never accepted.
+func (j *Judge) ServeTLS(ctx context.Context, listener net.Listener, config *tls.Config) error {
+	if config == nil || (len(config.Certificates) == 0 && config.GetCertificate == nil) {
+		_ = listener.Close()
+		return errors.New("tls config without certificate")
+	}
+	if config.MinVersion < tls.VersionTLS12 {
+		config = config.Clone()
+		config.MinVersion = tls.VersionTLS12
+	}
+	return j.serve(ctx, listener, config)
+}
+
+//tlsInfo describes the tls connection of the request, nil for plain http
+func tlsInfo(req *http.Request) *proxy.TLSInfo {
+	if req.TLS == nil {
+		return nil
+	}
+	return &proxy.TLSInfo{
+		Version:    tlsVersionName(req.TLS.Version),
+		Protocol:   req.TLS.NegotiatedProtocol,
+		ServerName: req.TLS.ServerName,
+	}
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("0x%04X", version)
+}
@@ -0,0 +1,118 @@
+package judge
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alekc/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJudge_ServeTLS(t *testing.T) {
+	cert, err := SelfSignedCertificate("localhost", "127.0.0.1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	j := newTestJudge(nil)
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	go func() {
+		_ = j.ServeTLS(context.Background(), listener, &tls.Config{Certificates: []tls.Certificate{cert}})
+	}()
+	defer j.Shutdown(context.Background())
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			ServerName:         "localhost",
+			NextProtos:         []string{"http/1.1"},
+		},
+	}}
+	resp, err := client.Get("https://" + listener.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	judgement := &proxy.Judgement{}
+	assert.NoError(t, judgement.UnmarshalJSON(body))
+	if assert.NotNil(t, judgement.TLS) {
+		assert.Equal(t, "TLS 1.3", judgement.TLS.Version)
+		assert.Equal(t, "http/1.1", judgement.TLS.Protocol)
+		assert.Equal(t, "localhost", judgement.TLS.ServerName)
+	}
+
+	//old tls versions are refused even if the config allows them
+	conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		MaxVersion:         tls.VersionTLS11,
+	})
+	if !assert.Error(t, err, "tls 1.1 handshake should fail") {
+		_ = conn.Close()
+	}
+
+	//plain http judgements don't contain tls info
+	req := newJudgeRequest("5.6.7.8:4000", "1.2.3.4", nil)
+	assert.Nil(t, j.Analyze(req).TLS)
+}
+
+func TestJudge_ServeTLSWithoutCertificate(t *testing.T) {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	assert.Error(t, Create().ServeTLS(context.Background(), listener, &tls.Config{}))
+}
+
+func TestCertificateLoader_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "judge")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	writeCertificate(t, certFile, keyFile, "first.example.com")
+	loader, err := NewCertificateLoader(certFile, keyFile)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "first.example.com", loadedName(t, loader))
+
+	writeCertificate(t, certFile, keyFile, "second.example.com")
+	assert.NoError(t, loader.Reload())
+	assert.Equal(t, "second.example.com", loadedName(t, loader))
+
+	//broken files keep the previous certificate
+	assert.NoError(t, ioutil.WriteFile(keyFile, []byte("broken"), 0600))
+	assert.Error(t, loader.Reload())
+	assert.Equal(t, "second.example.com", loadedName(t, loader))
+
+	_, err = NewCertificateLoader(certFile, filepath.Join(dir, "missing.pem"))
+	assert.Error(t, err)
+}
+
+func writeCertificate(t *testing.T, certFile, keyFile, host string) {
+	cert, err := SelfSignedCertificate(host)
+	assert.NoError(t, err)
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600))
+	assert.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0600))
+}
+
+func loadedName(t *testing.T, loader *CertificateLoader) string {
+	cert, err := loader.GetCertificate(nil)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if !assert.NoError(t, err) || len(parsed.DNSNames) == 0 {
+		return ""
+	}
+	return parsed.DNSNames[0]
+}
@@ -1,23 +1,227 @@
 package proxy
 
-import "net"
+import (
+	"fmt"
+	"net"
+)
+
+//FindingKind defines what kind of evidence has been found by the judge
+type FindingKind string
+
+//Known finding kinds
+const (
+	//FindingRealIP - real ip of the client has been found in a header
+	FindingRealIP FindingKind = "real_ip"
+	//FindingProxyHeader - header which is usually added by proxies is present
+	FindingProxyHeader FindingKind = "proxy_header"
+	//FindingHostname - reverse hostname of the proxy contains a known marker
+	FindingHostname FindingKind = "hostname"
+)
+
+//IPEncoding is the form in which the real ip has been found
+type IPEncoding string
+
+//Known ip encodings
+const (
+	//EncodingPlain - textual form of the ip (any valid ipv6 notation)
+	EncodingPlain IPEncoding = "plain"
+	//EncodingDashed - octets separated by dashes (i.e. 1-2-3-4.example.com)
+	EncodingDashed IPEncoding = "dashed"
+	//EncodingReversed - octets in the reversed order (i.e. 4.3.2.1.in-addr.arpa)
+	EncodingReversed IPEncoding = "reversed"
+	//EncodingHex - ip as a hexadecimal number (0x01020304)
+	EncodingHex IPEncoding = "hex"
+	//EncodingDecimal - ipv4 as a decimal integer (16909060)
+	EncodingDecimal IPEncoding = "decimal"
+	//EncodingIPv4Mapped - ipv4 mapped to ipv6 (::ffff:1.2.3.4)
+	EncodingIPv4Mapped IPEncoding = "ipv4-mapped"
+	//EncodingBase64 - base64 of the textual or binary form
+	EncodingBase64 IPEncoding = "base64"
+)
+
+//Severity of the finding
+type Severity string
+
+//Known severities
+const (
+	SeverityLow    Severity = "low"
+	SeverityMedium Severity = "medium"
+	SeverityHigh   Severity = "high"
+)
+
+//Finding is a single piece of evidence collected by the judge
+//easyjson:json
+type Finding struct {
+	Kind FindingKind `json:"kind"`
+	//Name of the header (if the finding relates to a header)
+	Header string `json:"header,omitempty"`
+	//Observed value (header value, resolved hostname etc.)
+	Value string `json:"value,omitempty"`
+	//Marker which has been matched (i.e. real ip, hostname marker or header marker)
+	Marker   string   `json:"marker,omitempty"`
+	Severity Severity `json:"severity"`
+	//Weight of the finding in the score, nil means the default weight of the severity
+	Weight *float64 `json:"weight,omitempty"`
+	//Form of the real ip (real ip findings only)
+	Encoding IPEncoding `json:"encoding,omitempty"`
+}
+
+//Message returns human readable description of the finding
+func (f *Finding) Message() string {
+	switch f.Kind {
+	case FindingRealIP:
+		if f.Encoding != "" && f.Encoding != EncodingPlain {
+			return fmt.Sprintf("Found real ip (%s) in the header [%s]", f.Encoding, f.Header)
+		}
+		return fmt.Sprintf("Found real ip in the header [%s]", f.Header)
+	case FindingProxyHeader:
+		return fmt.Sprintf("Header [%s] is present", f.Marker)
+	case FindingHostname:
+		return fmt.Sprintf("Hostname contains %s", f.Marker)
+	}
+	return fmt.Sprintf("%s: %s %s", f.Kind, f.Header, f.Value)
+}
 
 //Judgement contains information about a given proxy
 //easyjson:json
 type Judgement struct {
-	//Possible values
+	//Possible values (see AnonymityLevel)
 	// 0: Non Anon: Your ip is known, proxy usage is known
 	// 1: Non Anon: Your ip is known, proxy usage unknown
 	// 2: Semi Anon: Your ip is unknown, proxy usage known
 	// 3: Anon: Your ip is unknown, proxy usage unknown
-	AnonType int      `json:"anon_type"`
-	Messages []string `json:"messages"`
-	Country  string   `json:"country"`
-	RealIP   string   `json:"real_ip"`
-	RemoteIP net.IP   `json:"remote_ip"`
+	AnonType AnonymityLevel `json:"anon_type"`
+	//Human readable messages, kept for older clients. See Findings for the structured version.
+	Messages []string  `json:"messages"`
+	Findings []Finding `json:"findings"`
+	Country  string    `json:"country"`
+	RealIP   string    `json:"real_ip"`
+	RemoteIP net.IP    `json:"remote_ip"`
+	//Location and network owner of the proxy (see judge.GeoIP)
+	City string `json:"city,omitempty"`
+	ASN  uint   `json:"asn,omitempty"`
+	Org  string `json:"org,omitempty"`
+	//Categories of the proxy ip, i.e. datacenter, tor or vpn (see judge.Classifier)
+	Categories []string `json:"categories,omitempty"`
+	//Set only if the request reached the judge over tls
+	TLS *TLSInfo `json:"tls,omitempty"`
+	//Forwarding chains found in X-Forwarded-For and Forwarded headers
+	Forwarding []ForwardingChain `json:"forwarding,omitempty"`
+	//Entries of the Via header, oldest first
+	Via []ViaEntry `json:"via,omitempty"`
+	//Proxy software identified from the headers, in the order of the headers
+	ProxySoftware []Software `json:"proxy_software,omitempty"`
+	//Scores which decided the anonymity level
+	Score *Score `json:"score,omitempty"`
+}
+
+//judgementWithNames is used to serialize a judgement with the anonymity level name
+type judgementWithNames struct {
+	Judgement
+	AnonType string `json:"anon_type"`
+}
+
+//MarshalJSONNames works like MarshalJSON but encodes the anonymity level as a name (i.e. "elite")
+//instead of a number
+func (tr Judgement) MarshalJSONNames() ([]byte, error) {
+	if !tr.AnonType.IsValid() {
+		return nil, fmt.Errorf("invalid anonymity level: %d", int(tr.AnonType))
+	}
+	return judgementWithNames{Judgement: tr, AnonType: tr.AnonType.String()}.MarshalJSON()
+}
+
+//Score sums weights of the findings. The anonymity level is derived from the scores by the
+//scoring policy of the judge.
+type Score struct {
+	//Evidence of the real ip being visible
+	IPLeak float64 `json:"ip_leak"`
+	//Evidence of the proxy usage being visible
+	ProxyUsage float64 `json:"proxy_usage"`
+	//Contributions of the findings, in the order of Findings
+	Contributions []Contribution `json:"contributions"`
+}
+
+//Contribution is the weight added to the score by a single finding
+type Contribution struct {
+	Kind   FindingKind `json:"kind"`
+	Header string      `json:"header,omitempty"`
+	Marker string      `json:"marker,omitempty"`
+	//Score affected by the finding ("ip_leak" or "proxy_usage")
+	Implies string  `json:"implies"`
+	Weight  float64 `json:"weight"`
+}
+
+//ViaEntry is a single entry of the Via header (RFC 7230), i.e. "1.1 proxy.example.com:3128 (squid/3.5.27)"
+type ViaEntry struct {
+	//Received protocol, protocol name is omitted for http ("1.1", "HTTP/2.0")
+	Protocol   string `json:"protocol"`
+	ReceivedBy string `json:"received_by"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+//Software is a proxy software identified by a header
+type Software struct {
+	Name string `json:"name"`
+	//Empty if the version can't be identified
+	Version string `json:"version,omitempty"`
+	Header  string `json:"header"`
+	Value   string `json:"value"`
+}
+
+//ForwardingChain is the parsed content of a forwarding header, oldest (client) hop first
+type ForwardingChain struct {
+	Header string          `json:"header"`
+	Hops   []ForwardingHop `json:"hops"`
+	//Number of hops in the chain
+	HopCount int `json:"hop_count"`
+	//Number of hops which aren't trusted gateways or edge servers (proxies)
+	UntrustedHops int `json:"untrusted_hops"`
+	//Real ip of the client is present in the chain
+	ContainsRealIP bool `json:"contains_real_ip"`
+	//Untrusted private (or loopback) address is present in the chain, leaking internal network
+	PrivateLeak bool `json:"private_leak"`
+}
+
+//ForwardingHop is a single entry of the forwarding chain
+type ForwardingHop struct {
+	//Original value of the entry (including the port)
+	Value string `json:"value"`
+	//Nil for unknown or obfuscated entries
+	IP      net.IP `json:"ip,omitempty"`
+	Trusted bool   `json:"trusted,omitempty"`
+	Edge    bool   `json:"edge,omitempty"`
+	Private bool   `json:"private,omitempty"`
+	RealIP  bool   `json:"real_ip,omitempty"`
+}
+
+//TLSInfo describes the tls connection used to reach the judge
+type TLSInfo struct {
+	//Tls version, i.e. "TLS 1.3"
+	Version string `json:"version"`
+	//Protocol negotiated with ALPN (i.e. "h2"), empty if ALPN hasn't been used
+	Protocol   string `json:"protocol,omitempty"`
+	ServerName string `json:"server_name,omitempty"`
 }
 
 //AppendMessages appends result messages
 func (tr *Judgement) AppendMessages(msg []string) {
 	tr.Messages = append(tr.Messages, msg...)
 }
+
+//AppendFindings appends findings and their messages
+func (tr *Judgement) AppendFindings(findings []Finding) {
+	for i := range findings {
+		tr.Findings = append(tr.Findings, findings[i])
+		tr.Messages = append(tr.Messages, findings[i].Message())
+	}
+}
+
+//HasFinding returns true if the judgement contains at least one finding of the given kind
+func (tr *Judgement) HasFinding(kind FindingKind) bool {
+	for i := range tr.Findings {
+		if tr.Findings[i].Kind == kind {
+			return true
+		}
+	}
+	return false
+}
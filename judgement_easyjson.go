@@ -17,7 +17,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonB2c4060bDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *Judgement) {
+func easyjsonB2c4060bDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *judgementWithNames) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -37,7 +37,7 @@ func easyjsonB2c4060bDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *Judgement)
 		}
 		switch key {
 		case "anon_type":
-			out.AnonType = int(in.Int())
+			out.AnonType = string(in.String())
 		case "messages":
 			if in.IsNull() {
 				in.Skip()
@@ -61,6 +61,29 @@ func easyjsonB2c4060bDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *Judgement)
 				}
 				in.Delim(']')
 			}
+		case "findings":
+			if in.IsNull() {
+				in.Skip()
+				out.Findings = nil
+			} else {
+				in.Delim('[')
+				if out.Findings == nil {
+					if !in.IsDelim(']') {
+						out.Findings = make([]Finding, 0, 1)
+					} else {
+						out.Findings = []Finding{}
+					}
+				} else {
+					out.Findings = (out.Findings)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v2 Finding
+					(v2).UnmarshalEasyJSON(in)
+					out.Findings = append(out.Findings, v2)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		case "country":
 			out.Country = string(in.String())
 		case "real_ip":
@@ -69,6 +92,124 @@ func easyjsonB2c4060bDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *Judgement)
 			if data := in.UnsafeBytes(); in.Ok() {
 				in.AddError((out.RemoteIP).UnmarshalText(data))
 			}
+		case "city":
+			out.City = string(in.String())
+		case "asn":
+			out.ASN = uint(in.Uint())
+		case "org":
+			out.Org = string(in.String())
+		case "categories":
+			if in.IsNull() {
+				in.Skip()
+				out.Categories = nil
+			} else {
+				in.Delim('[')
+				if out.Categories == nil {
+					if !in.IsDelim(']') {
+						out.Categories = make([]string, 0, 4)
+					} else {
+						out.Categories = []string{}
+					}
+				} else {
+					out.Categories = (out.Categories)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v3 string
+					v3 = string(in.String())
+					out.Categories = append(out.Categories, v3)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "tls":
+			if in.IsNull() {
+				in.Skip()
+				out.TLS = nil
+			} else {
+				if out.TLS == nil {
+					out.TLS = new(TLSInfo)
+				}
+				(*out.TLS).UnmarshalEasyJSON(in)
+			}
+		case "forwarding":
+			if in.IsNull() {
+				in.Skip()
+				out.Forwarding = nil
+			} else {
+				in.Delim('[')
+				if out.Forwarding == nil {
+					if !in.IsDelim(']') {
+						out.Forwarding = make([]ForwardingChain, 0, 1)
+					} else {
+						out.Forwarding = []ForwardingChain{}
+					}
+				} else {
+					out.Forwarding = (out.Forwarding)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 ForwardingChain
+					(v4).UnmarshalEasyJSON(in)
+					out.Forwarding = append(out.Forwarding, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "via":
+			if in.IsNull() {
+				in.Skip()
+				out.Via = nil
+			} else {
+				in.Delim('[')
+				if out.Via == nil {
+					if !in.IsDelim(']') {
+						out.Via = make([]ViaEntry, 0, 1)
+					} else {
+						out.Via = []ViaEntry{}
+					}
+				} else {
+					out.Via = (out.Via)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 ViaEntry
+					(v5).UnmarshalEasyJSON(in)
+					out.Via = append(out.Via, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "proxy_software":
+			if in.IsNull() {
+				in.Skip()
+				out.ProxySoftware = nil
+			} else {
+				in.Delim('[')
+				if out.ProxySoftware == nil {
+					if !in.IsDelim(']') {
+						out.ProxySoftware = make([]Software, 0, 1)
+					} else {
+						out.ProxySoftware = []Software{}
+					}
+				} else {
+					out.ProxySoftware = (out.ProxySoftware)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v6 Software
+					(v6).UnmarshalEasyJSON(in)
+					out.ProxySoftware = append(out.ProxySoftware, v6)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "score":
+			if in.IsNull() {
+				in.Skip()
+				out.Score = nil
+			} else {
+				if out.Score == nil {
+					out.Score = new(Score)
+				}
+				(*out.Score).UnmarshalEasyJSON(in)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -79,7 +220,7 @@ func easyjsonB2c4060bDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *Judgement)
 		in.Consumed()
 	}
 }
-func easyjsonB2c4060bEncodeGithubComAlekcProxy(out *jwriter.Writer, in Judgement) {
+func easyjsonB2c4060bEncodeGithubComAlekcProxy(out *jwriter.Writer, in judgementWithNames) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -91,7 +232,7 @@ func easyjsonB2c4060bEncodeGithubComAlekcProxy(out *jwriter.Writer, in Judgement
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int(int(in.AnonType))
+		out.String(string(in.AnonType))
 	}
 	{
 		const prefix string = ",\"messages\":"
@@ -105,11 +246,32 @@ func easyjsonB2c4060bEncodeGithubComAlekcProxy(out *jwriter.Writer, in Judgement
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v2, v3 := range in.Messages {
-				if v2 > 0 {
+			for v7, v8 := range in.Messages {
+				if v7 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v8))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"findings\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Findings == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v9, v10 := range in.Findings {
+				if v9 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v3))
+				(v10).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -144,29 +306,1588 @@ func easyjsonB2c4060bEncodeGithubComAlekcProxy(out *jwriter.Writer, in Judgement
 		}
 		out.RawText((in.RemoteIP).MarshalText())
 	}
+	if in.City != "" {
+		const prefix string = ",\"city\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.City))
+	}
+	if in.ASN != 0 {
+		const prefix string = ",\"asn\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint(uint(in.ASN))
+	}
+	if in.Org != "" {
+		const prefix string = ",\"org\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Org))
+	}
+	if len(in.Categories) != 0 {
+		const prefix string = ",\"categories\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v11, v12 := range in.Categories {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v12))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.TLS != nil {
+		const prefix string = ",\"tls\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.TLS).MarshalEasyJSON(out)
+	}
+	if len(in.Forwarding) != 0 {
+		const prefix string = ",\"forwarding\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v13, v14 := range in.Forwarding {
+				if v13 > 0 {
+					out.RawByte(',')
+				}
+				(v14).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Via) != 0 {
+		const prefix string = ",\"via\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v15, v16 := range in.Via {
+				if v15 > 0 {
+					out.RawByte(',')
+				}
+				(v16).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.ProxySoftware) != 0 {
+		const prefix string = ",\"proxy_software\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v17, v18 := range in.ProxySoftware {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				(v18).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Score != nil {
+		const prefix string = ",\"score\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Score).MarshalEasyJSON(out)
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Judgement) MarshalJSON() ([]byte, error) {
+func (v judgementWithNames) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonB2c4060bEncodeGithubComAlekcProxy(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Judgement) MarshalEasyJSON(w *jwriter.Writer) {
+func (v judgementWithNames) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonB2c4060bEncodeGithubComAlekcProxy(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Judgement) UnmarshalJSON(data []byte) error {
+func (v *judgementWithNames) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonB2c4060bDecodeGithubComAlekcProxy(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Judgement) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *judgementWithNames) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonB2c4060bDecodeGithubComAlekcProxy(l, v)
 }
+func easyjsonB2c4060bDecodeGithubComAlekcProxy1(in *jlexer.Lexer, out *ViaEntry) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "protocol":
+			out.Protocol = string(in.String())
+		case "received_by":
+			out.ReceivedBy = string(in.String())
+		case "comment":
+			out.Comment = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonB2c4060bEncodeGithubComAlekcProxy1(out *jwriter.Writer, in ViaEntry) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"protocol\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Protocol))
+	}
+	{
+		const prefix string = ",\"received_by\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ReceivedBy))
+	}
+	if in.Comment != "" {
+		const prefix string = ",\"comment\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Comment))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ViaEntry) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonB2c4060bEncodeGithubComAlekcProxy1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ViaEntry) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonB2c4060bEncodeGithubComAlekcProxy1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ViaEntry) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonB2c4060bDecodeGithubComAlekcProxy1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ViaEntry) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonB2c4060bDecodeGithubComAlekcProxy1(l, v)
+}
+func easyjsonB2c4060bDecodeGithubComAlekcProxy2(in *jlexer.Lexer, out *TLSInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "version":
+			out.Version = string(in.String())
+		case "protocol":
+			out.Protocol = string(in.String())
+		case "server_name":
+			out.ServerName = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonB2c4060bEncodeGithubComAlekcProxy2(out *jwriter.Writer, in TLSInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"version\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Version))
+	}
+	if in.Protocol != "" {
+		const prefix string = ",\"protocol\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Protocol))
+	}
+	if in.ServerName != "" {
+		const prefix string = ",\"server_name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ServerName))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TLSInfo) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonB2c4060bEncodeGithubComAlekcProxy2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TLSInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonB2c4060bEncodeGithubComAlekcProxy2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TLSInfo) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonB2c4060bDecodeGithubComAlekcProxy2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TLSInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonB2c4060bDecodeGithubComAlekcProxy2(l, v)
+}
+func easyjsonB2c4060bDecodeGithubComAlekcProxy3(in *jlexer.Lexer, out *Software) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "version":
+			out.Version = string(in.String())
+		case "header":
+			out.Header = string(in.String())
+		case "value":
+			out.Value = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonB2c4060bEncodeGithubComAlekcProxy3(out *jwriter.Writer, in Software) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	if in.Version != "" {
+		const prefix string = ",\"version\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Version))
+	}
+	{
+		const prefix string = ",\"header\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Header))
+	}
+	{
+		const prefix string = ",\"value\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Value))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Software) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonB2c4060bEncodeGithubComAlekcProxy3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Software) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonB2c4060bEncodeGithubComAlekcProxy3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Software) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonB2c4060bDecodeGithubComAlekcProxy3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Software) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonB2c4060bDecodeGithubComAlekcProxy3(l, v)
+}
+func easyjsonB2c4060bDecodeGithubComAlekcProxy4(in *jlexer.Lexer, out *Score) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "ip_leak":
+			out.IPLeak = float64(in.Float64())
+		case "proxy_usage":
+			out.ProxyUsage = float64(in.Float64())
+		case "contributions":
+			if in.IsNull() {
+				in.Skip()
+				out.Contributions = nil
+			} else {
+				in.Delim('[')
+				if out.Contributions == nil {
+					if !in.IsDelim(']') {
+						out.Contributions = make([]Contribution, 0, 1)
+					} else {
+						out.Contributions = []Contribution{}
+					}
+				} else {
+					out.Contributions = (out.Contributions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 Contribution
+					(v19).UnmarshalEasyJSON(in)
+					out.Contributions = append(out.Contributions, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonB2c4060bEncodeGithubComAlekcProxy4(out *jwriter.Writer, in Score) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"ip_leak\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Float64(float64(in.IPLeak))
+	}
+	{
+		const prefix string = ",\"proxy_usage\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Float64(float64(in.ProxyUsage))
+	}
+	{
+		const prefix string = ",\"contributions\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Contributions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v20, v21 := range in.Contributions {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				(v21).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Score) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonB2c4060bEncodeGithubComAlekcProxy4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Score) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonB2c4060bEncodeGithubComAlekcProxy4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Score) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonB2c4060bDecodeGithubComAlekcProxy4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Score) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonB2c4060bDecodeGithubComAlekcProxy4(l, v)
+}
+func easyjsonB2c4060bDecodeGithubComAlekcProxy5(in *jlexer.Lexer, out *Judgement) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "anon_type":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.AnonType).UnmarshalJSON(data))
+			}
+		case "messages":
+			if in.IsNull() {
+				in.Skip()
+				out.Messages = nil
+			} else {
+				in.Delim('[')
+				if out.Messages == nil {
+					if !in.IsDelim(']') {
+						out.Messages = make([]string, 0, 4)
+					} else {
+						out.Messages = []string{}
+					}
+				} else {
+					out.Messages = (out.Messages)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v22 string
+					v22 = string(in.String())
+					out.Messages = append(out.Messages, v22)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "findings":
+			if in.IsNull() {
+				in.Skip()
+				out.Findings = nil
+			} else {
+				in.Delim('[')
+				if out.Findings == nil {
+					if !in.IsDelim(']') {
+						out.Findings = make([]Finding, 0, 1)
+					} else {
+						out.Findings = []Finding{}
+					}
+				} else {
+					out.Findings = (out.Findings)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v23 Finding
+					(v23).UnmarshalEasyJSON(in)
+					out.Findings = append(out.Findings, v23)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "country":
+			out.Country = string(in.String())
+		case "real_ip":
+			out.RealIP = string(in.String())
+		case "remote_ip":
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.RemoteIP).UnmarshalText(data))
+			}
+		case "city":
+			out.City = string(in.String())
+		case "asn":
+			out.ASN = uint(in.Uint())
+		case "org":
+			out.Org = string(in.String())
+		case "categories":
+			if in.IsNull() {
+				in.Skip()
+				out.Categories = nil
+			} else {
+				in.Delim('[')
+				if out.Categories == nil {
+					if !in.IsDelim(']') {
+						out.Categories = make([]string, 0, 4)
+					} else {
+						out.Categories = []string{}
+					}
+				} else {
+					out.Categories = (out.Categories)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v24 string
+					v24 = string(in.String())
+					out.Categories = append(out.Categories, v24)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "tls":
+			if in.IsNull() {
+				in.Skip()
+				out.TLS = nil
+			} else {
+				if out.TLS == nil {
+					out.TLS = new(TLSInfo)
+				}
+				(*out.TLS).UnmarshalEasyJSON(in)
+			}
+		case "forwarding":
+			if in.IsNull() {
+				in.Skip()
+				out.Forwarding = nil
+			} else {
+				in.Delim('[')
+				if out.Forwarding == nil {
+					if !in.IsDelim(']') {
+						out.Forwarding = make([]ForwardingChain, 0, 1)
+					} else {
+						out.Forwarding = []ForwardingChain{}
+					}
+				} else {
+					out.Forwarding = (out.Forwarding)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v25 ForwardingChain
+					(v25).UnmarshalEasyJSON(in)
+					out.Forwarding = append(out.Forwarding, v25)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "via":
+			if in.IsNull() {
+				in.Skip()
+				out.Via = nil
+			} else {
+				in.Delim('[')
+				if out.Via == nil {
+					if !in.IsDelim(']') {
+						out.Via = make([]ViaEntry, 0, 1)
+					} else {
+						out.Via = []ViaEntry{}
+					}
+				} else {
+					out.Via = (out.Via)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v26 ViaEntry
+					(v26).UnmarshalEasyJSON(in)
+					out.Via = append(out.Via, v26)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "proxy_software":
+			if in.IsNull() {
+				in.Skip()
+				out.ProxySoftware = nil
+			} else {
+				in.Delim('[')
+				if out.ProxySoftware == nil {
+					if !in.IsDelim(']') {
+						out.ProxySoftware = make([]Software, 0, 1)
+					} else {
+						out.ProxySoftware = []Software{}
+					}
+				} else {
+					out.ProxySoftware = (out.ProxySoftware)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v27 Software
+					(v27).UnmarshalEasyJSON(in)
+					out.ProxySoftware = append(out.ProxySoftware, v27)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "score":
+			if in.IsNull() {
+				in.Skip()
+				out.Score = nil
+			} else {
+				if out.Score == nil {
+					out.Score = new(Score)
+				}
+				(*out.Score).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonB2c4060bEncodeGithubComAlekcProxy5(out *jwriter.Writer, in Judgement) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"anon_type\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.AnonType).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"messages\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Messages == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v28, v29 := range in.Messages {
+				if v28 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v29))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"findings\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Findings == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v30, v31 := range in.Findings {
+				if v30 > 0 {
+					out.RawByte(',')
+				}
+				(v31).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"country\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Country))
+	}
+	{
+		const prefix string = ",\"real_ip\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.RealIP))
+	}
+	{
+		const prefix string = ",\"remote_ip\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.RawText((in.RemoteIP).MarshalText())
+	}
+	if in.City != "" {
+		const prefix string = ",\"city\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.City))
+	}
+	if in.ASN != 0 {
+		const prefix string = ",\"asn\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint(uint(in.ASN))
+	}
+	if in.Org != "" {
+		const prefix string = ",\"org\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Org))
+	}
+	if len(in.Categories) != 0 {
+		const prefix string = ",\"categories\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v32, v33 := range in.Categories {
+				if v32 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v33))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.TLS != nil {
+		const prefix string = ",\"tls\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.TLS).MarshalEasyJSON(out)
+	}
+	if len(in.Forwarding) != 0 {
+		const prefix string = ",\"forwarding\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v34, v35 := range in.Forwarding {
+				if v34 > 0 {
+					out.RawByte(',')
+				}
+				(v35).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Via) != 0 {
+		const prefix string = ",\"via\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v36, v37 := range in.Via {
+				if v36 > 0 {
+					out.RawByte(',')
+				}
+				(v37).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.ProxySoftware) != 0 {
+		const prefix string = ",\"proxy_software\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v38, v39 := range in.ProxySoftware {
+				if v38 > 0 {
+					out.RawByte(',')
+				}
+				(v39).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Score != nil {
+		const prefix string = ",\"score\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Score).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Judgement) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonB2c4060bEncodeGithubComAlekcProxy5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Judgement) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonB2c4060bEncodeGithubComAlekcProxy5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Judgement) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonB2c4060bDecodeGithubComAlekcProxy5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Judgement) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonB2c4060bDecodeGithubComAlekcProxy5(l, v)
+}
+func easyjsonB2c4060bDecodeGithubComAlekcProxy6(in *jlexer.Lexer, out *ForwardingHop) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "value":
+			out.Value = string(in.String())
+		case "ip":
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.IP).UnmarshalText(data))
+			}
+		case "trusted":
+			out.Trusted = bool(in.Bool())
+		case "edge":
+			out.Edge = bool(in.Bool())
+		case "private":
+			out.Private = bool(in.Bool())
+		case "real_ip":
+			out.RealIP = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonB2c4060bEncodeGithubComAlekcProxy6(out *jwriter.Writer, in ForwardingHop) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"value\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Value))
+	}
+	if len(in.IP) != 0 {
+		const prefix string = ",\"ip\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.RawText((in.IP).MarshalText())
+	}
+	if in.Trusted {
+		const prefix string = ",\"trusted\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Trusted))
+	}
+	if in.Edge {
+		const prefix string = ",\"edge\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Edge))
+	}
+	if in.Private {
+		const prefix string = ",\"private\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Private))
+	}
+	if in.RealIP {
+		const prefix string = ",\"real_ip\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.RealIP))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForwardingHop) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonB2c4060bEncodeGithubComAlekcProxy6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForwardingHop) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonB2c4060bEncodeGithubComAlekcProxy6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForwardingHop) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonB2c4060bDecodeGithubComAlekcProxy6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForwardingHop) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonB2c4060bDecodeGithubComAlekcProxy6(l, v)
+}
+func easyjsonB2c4060bDecodeGithubComAlekcProxy7(in *jlexer.Lexer, out *ForwardingChain) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "header":
+			out.Header = string(in.String())
+		case "hops":
+			if in.IsNull() {
+				in.Skip()
+				out.Hops = nil
+			} else {
+				in.Delim('[')
+				if out.Hops == nil {
+					if !in.IsDelim(']') {
+						out.Hops = make([]ForwardingHop, 0, 1)
+					} else {
+						out.Hops = []ForwardingHop{}
+					}
+				} else {
+					out.Hops = (out.Hops)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v40 ForwardingHop
+					(v40).UnmarshalEasyJSON(in)
+					out.Hops = append(out.Hops, v40)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "hop_count":
+			out.HopCount = int(in.Int())
+		case "untrusted_hops":
+			out.UntrustedHops = int(in.Int())
+		case "contains_real_ip":
+			out.ContainsRealIP = bool(in.Bool())
+		case "private_leak":
+			out.PrivateLeak = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonB2c4060bEncodeGithubComAlekcProxy7(out *jwriter.Writer, in ForwardingChain) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"header\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Header))
+	}
+	{
+		const prefix string = ",\"hops\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Hops == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v41, v42 := range in.Hops {
+				if v41 > 0 {
+					out.RawByte(',')
+				}
+				(v42).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"hop_count\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.HopCount))
+	}
+	{
+		const prefix string = ",\"untrusted_hops\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.UntrustedHops))
+	}
+	{
+		const prefix string = ",\"contains_real_ip\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.ContainsRealIP))
+	}
+	{
+		const prefix string = ",\"private_leak\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.PrivateLeak))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForwardingChain) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonB2c4060bEncodeGithubComAlekcProxy7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForwardingChain) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonB2c4060bEncodeGithubComAlekcProxy7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForwardingChain) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonB2c4060bDecodeGithubComAlekcProxy7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForwardingChain) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonB2c4060bDecodeGithubComAlekcProxy7(l, v)
+}
+func easyjsonB2c4060bDecodeGithubComAlekcProxy8(in *jlexer.Lexer, out *Finding) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "kind":
+			out.Kind = FindingKind(in.String())
+		case "header":
+			out.Header = string(in.String())
+		case "value":
+			out.Value = string(in.String())
+		case "marker":
+			out.Marker = string(in.String())
+		case "severity":
+			out.Severity = Severity(in.String())
+		case "weight":
+			if in.IsNull() {
+				in.Skip()
+				out.Weight = nil
+			} else {
+				if out.Weight == nil {
+					out.Weight = new(float64)
+				}
+				*out.Weight = float64(in.Float64())
+			}
+		case "encoding":
+			out.Encoding = IPEncoding(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonB2c4060bEncodeGithubComAlekcProxy8(out *jwriter.Writer, in Finding) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"kind\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Kind))
+	}
+	if in.Header != "" {
+		const prefix string = ",\"header\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Header))
+	}
+	if in.Value != "" {
+		const prefix string = ",\"value\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Value))
+	}
+	if in.Marker != "" {
+		const prefix string = ",\"marker\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Marker))
+	}
+	{
+		const prefix string = ",\"severity\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Severity))
+	}
+	if in.Weight != nil {
+		const prefix string = ",\"weight\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Float64(float64(*in.Weight))
+	}
+	if in.Encoding != "" {
+		const prefix string = ",\"encoding\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Encoding))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Finding) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonB2c4060bEncodeGithubComAlekcProxy8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Finding) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonB2c4060bEncodeGithubComAlekcProxy8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Finding) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonB2c4060bDecodeGithubComAlekcProxy8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Finding) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonB2c4060bDecodeGithubComAlekcProxy8(l, v)
+}
+func easyjsonB2c4060bDecodeGithubComAlekcProxy9(in *jlexer.Lexer, out *Contribution) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "kind":
+			out.Kind = FindingKind(in.String())
+		case "header":
+			out.Header = string(in.String())
+		case "marker":
+			out.Marker = string(in.String())
+		case "implies":
+			out.Implies = string(in.String())
+		case "weight":
+			out.Weight = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonB2c4060bEncodeGithubComAlekcProxy9(out *jwriter.Writer, in Contribution) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"kind\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Kind))
+	}
+	if in.Header != "" {
+		const prefix string = ",\"header\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Header))
+	}
+	if in.Marker != "" {
+		const prefix string = ",\"marker\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Marker))
+	}
+	{
+		const prefix string = ",\"implies\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Implies))
+	}
+	{
+		const prefix string = ",\"weight\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Float64(float64(in.Weight))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Contribution) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonB2c4060bEncodeGithubComAlekcProxy9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Contribution) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonB2c4060bEncodeGithubComAlekcProxy9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Contribution) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonB2c4060bDecodeGithubComAlekcProxy9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Contribution) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonB2c4060bDecodeGithubComAlekcProxy9(l, v)
+}
@@ -0,0 +1,58 @@
+package proxy
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJudgement_AppendFindings(t *testing.T) {
+	judgement := &Judgement{}
+	judgement.AppendFindings([]Finding{
+		{Kind: FindingRealIP, Header: "X-Forwarded-For", Value: "1.2.3.4", Marker: "1.2.3.4", Severity: SeverityHigh},
+		{Kind: FindingProxyHeader, Header: "Via", Value: "1.1 squid", Marker: "Via", Severity: SeverityMedium},
+		{Kind: FindingHostname, Value: "cache.example.com.", Marker: "cache", Severity: SeverityLow},
+	})
+	assert.Equal(t, []string{
+		"Found real ip in the header [X-Forwarded-For]",
+		"Header [Via] is present",
+		"Hostname contains cache",
+	}, judgement.Messages, "messages should be kept for older clients")
+	assert.True(t, judgement.HasFinding(FindingRealIP))
+
+	encoded, err := judgement.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `{"kind":"real_ip","header":"X-Forwarded-For","value":"1.2.3.4","marker":"1.2.3.4","severity":"high"}`)
+
+	decoded := &Judgement{}
+	assert.NoError(t, decoded.UnmarshalJSON(encoded))
+	assert.Equal(t, judgement.Findings, decoded.Findings)
+}
+
+func TestJudgement_Forwarding(t *testing.T) {
+	judgement := &Judgement{
+		Forwarding: []ForwardingChain{{
+			Header: "X-Forwarded-For",
+			Hops: []ForwardingHop{
+				{Value: "1.2.3.4", IP: net.ParseIP("1.2.3.4"), RealIP: true},
+				{Value: "unknown"},
+			},
+			HopCount:       2,
+			UntrustedHops:  2,
+			ContainsRealIP: true,
+		}},
+	}
+
+	encoded, err := judgement.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"hops":[{"value":"1.2.3.4","ip":"1.2.3.4","real_ip":true},{"value":"unknown"}],"hop_count":2`)
+
+	decoded := &Judgement{}
+	assert.NoError(t, decoded.UnmarshalJSON(encoded))
+	assert.Equal(t, judgement.Forwarding, decoded.Forwarding)
+
+	//forwarding is omitted if there are no chains
+	encoded, _ = (&Judgement{}).MarshalJSON()
+	assert.NotContains(t, string(encoded), "forwarding")
+}
@@ -0,0 +1,222 @@
+package pool
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/proxy/tester"
+)
+
+//ErrEmpty is returned by Next when there is no proxy matching pool filters
+var ErrEmpty = errors.New("no proxy available")
+
+//DefaultMaxFailures is the number of consecutive failures after which a proxy is evicted
+const DefaultMaxFailures = 3
+
+//Entry is a proxy held by the pool together with its latest check results
+type Entry struct {
+	Proxy     *proxy.Proxy
+	Result    *tester.Result
+	Judgement *proxy.Judgement
+	//Latency of the proxy. Initialized from the check result and updated by MarkSuccess
+	Latency time.Duration
+	//Last time the proxy has been returned by Next
+	LastUsed time.Time
+	//Number of consecutive failures
+	Failures  int
+	Successes int
+
+	//entry matches pool filters and is in the candidate list
+	candidate bool
+}
+
+//Pool holds checked proxies and hands them out according to the strategy.
+//All methods are safe for concurrent use.
+type Pool struct {
+	//Number of consecutive failures after which the proxy is removed from the pool
+	MaxFailures int
+
+	mu       sync.Mutex
+	strategy Strategy
+	filters  []Filter
+	entries  []*Entry
+	index    map[string]*Entry
+	//entries matching the filters, kept up to date by Add and remove
+	candidates []*Entry
+}
+
+//New creates new pool using given strategy (RoundRobin if nil). Only proxies matching all filters
+//are returned by Next. Filters are evaluated when the proxy is added or its check results are updated.
+func New(strategy Strategy, filters ...Filter) *Pool {
+	if strategy == nil {
+		strategy = NewRoundRobin()
+	}
+	return &Pool{
+		MaxFailures: DefaultMaxFailures,
+		strategy:    strategy,
+		filters:     filters,
+		index:       make(map[string]*Entry),
+	}
+}
+
+//Add adds proxy to the pool or updates its check results if it's already present.
+//If judgement is nil, it's decoded from the result body (when possible).
+func (p *Pool) Add(px *proxy.Proxy, result *tester.Result, judgement *proxy.Judgement) {
+	if judgement == nil && result != nil {
+		judgement, _ = result.Judgement()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.index[px.Key()]
+	if !ok {
+		entry = &Entry{Proxy: px}
+		p.index[px.Key()] = entry
+		p.entries = append(p.entries, entry)
+	}
+	entry.Result = result
+	entry.Judgement = judgement
+	entry.Failures = 0
+	if result != nil && result.ExecTime > 0 {
+		entry.Latency = result.ExecTime
+	}
+
+	matches := p.matches(entry)
+	if matches && !entry.candidate {
+		entry.candidate = true
+		p.candidates = append(p.candidates, entry)
+	} else if !matches && entry.candidate {
+		p.removeCandidate(entry)
+	}
+}
+
+//AddChecked adds proxy to the pool only if the check has been successful (see tester.Check).
+//Returns true if the proxy has been added.
+func (p *Pool) AddChecked(px *proxy.Proxy, result *tester.Result) bool {
+	if px == nil || result == nil || !result.Ok {
+		return false
+	}
+	p.Add(px, result, nil)
+	return true
+}
+
+//Remove removes proxy from the pool
+func (p *Pool) Remove(px *proxy.Proxy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.remove(px.Key())
+}
+
+func (p *Pool) remove(k string) {
+	entry, ok := p.index[k]
+	if !ok {
+		return
+	}
+	delete(p.index, k)
+	for i, e := range p.entries {
+		if e == entry {
+			p.entries = append(p.entries[:i], p.entries[i+1:]...)
+			break
+		}
+	}
+	if entry.candidate {
+		p.removeCandidate(entry)
+	}
+}
+
+func (p *Pool) removeCandidate(entry *Entry) {
+	entry.candidate = false
+	for i, e := range p.candidates {
+		if e == entry {
+			p.candidates = append(p.candidates[:i], p.candidates[i+1:]...)
+			return
+		}
+	}
+}
+
+//Next returns next proxy according to the pool strategy. ErrEmpty is returned if there
+//are no proxies matching pool filters.
+func (p *Pool) Next() (*proxy.Proxy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.candidates) == 0 {
+		return nil, ErrEmpty
+	}
+
+	entry := p.strategy.Select(p.candidates)
+	entry.LastUsed = time.Now()
+	return entry.Proxy, nil
+}
+
+func (p *Pool) matches(entry *Entry) bool {
+	for _, filter := range p.filters {
+		if !filter(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+//MarkSuccess records successful usage of the proxy. If latency is greater than zero,
+//it's blended into the proxy latency.
+func (p *Pool) MarkSuccess(px *proxy.Proxy, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.index[px.Key()]
+	if !ok {
+		return
+	}
+	entry.Failures = 0
+	entry.Successes++
+	if latency > 0 {
+		if entry.Latency == 0 {
+			entry.Latency = latency
+		} else {
+			//exponential moving average, recent measurements weight 1/4
+			entry.Latency = (entry.Latency*3 + latency) / 4
+		}
+	}
+}
+
+//MarkFailed records failed usage of the proxy. Proxy is evicted after MaxFailures
+//consecutive failures. Returns true if the proxy has been evicted.
+func (p *Pool) MarkFailed(px *proxy.Proxy) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k := px.Key()
+	entry, ok := p.index[k]
+	if !ok {
+		return false
+	}
+	entry.Failures++
+	if p.MaxFailures > 0 && entry.Failures >= p.MaxFailures {
+		p.remove(k)
+		return true
+	}
+	return false
+}
+
+//Len returns the number of proxies in the pool
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+//Entries returns a snapshot of all entries in the pool
+func (p *Pool) Entries() []Entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := make([]Entry, 0, len(p.entries))
+	for _, entry := range p.entries {
+		result = append(result, *entry)
+	}
+	return result
+}
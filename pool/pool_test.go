@@ -0,0 +1,132 @@
+package pool
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/proxy/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func testProxy(i int) *proxy.Proxy {
+	return &proxy.Proxy{IP: net.IPv4(10, 0, 0, byte(i)), Port: 8080, Type: proxy.TypeHTTP}
+}
+
+func TestPool_RoundRobin(t *testing.T) {
+	p := New(nil)
+	_, err := p.Next()
+	assert.Equal(t, ErrEmpty, err)
+
+	for i := 1; i <= 3; i++ {
+		p.Add(testProxy(i), nil, nil)
+	}
+	p.Add(testProxy(1), nil, nil)
+	assert.Equal(t, 3, p.Len(), "duplicates should not be added")
+
+	for _, expected := range []int{1, 2, 3, 1} {
+		px, err := p.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, testProxy(expected), px)
+	}
+}
+
+func TestPool_Filters(t *testing.T) {
+	p := New(LeastRecentlyUsed(), MinAnonymity(proxy.AnonAnonymous), Country("de"))
+	p.Add(testProxy(1), nil, &proxy.Judgement{AnonType: proxy.AnonElite, Country: "DE"})
+	p.Add(testProxy(2), nil, &proxy.Judgement{AnonType: proxy.AnonTransparent, Country: "DE"})
+	p.Add(testProxy(3), nil, &proxy.Judgement{AnonType: proxy.AnonElite, Country: "US"})
+	p.Add(testProxy(4), nil, nil)
+	p.Add(testProxy(5), &tester.Result{Ok: true, Body: `{"anon_type":2,"country":"DE"}`}, nil)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 4; i++ {
+		px, err := p.Next()
+		assert.NoError(t, err)
+		seen[px.ToString()] = true
+	}
+	assert.Equal(t, map[string]bool{"10.0.0.1:8080": true, "10.0.0.5:8080": true}, seen)
+
+	//filters are evaluated again when the check results are updated
+	p.Add(testProxy(1), nil, &proxy.Judgement{AnonType: proxy.AnonTransparent, Country: "DE"})
+	p.Add(testProxy(2), nil, &proxy.Judgement{AnonType: proxy.AnonElite, Country: "DE"})
+	p.Remove(testProxy(5))
+	for i := 0; i < 2; i++ {
+		px, err := p.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, testProxy(2), px)
+	}
+}
+
+func TestPool_NextDoesNotAllocate(t *testing.T) {
+	p := New(nil, Type(proxy.TypeHTTP))
+	for i := 1; i <= 10; i++ {
+		p.Add(testProxy(i), nil, nil)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = p.Next()
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func TestPool_MarkFailed(t *testing.T) {
+	p := New(nil)
+	p.MaxFailures = 2
+	p.Add(testProxy(1), nil, nil)
+	p.Add(testProxy(2), nil, nil)
+
+	assert.False(t, p.MarkFailed(testProxy(1)))
+	p.MarkSuccess(testProxy(1), time.Second)
+	assert.False(t, p.MarkFailed(testProxy(1)), "success should reset failure counter")
+	assert.True(t, p.MarkFailed(testProxy(1)))
+	assert.Equal(t, 1, p.Len())
+
+	px, err := p.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, testProxy(2), px)
+}
+
+func TestPool_WeightedRandom(t *testing.T) {
+	p := New(NewWeightedRandom())
+	p.Add(testProxy(1), &tester.Result{ExecTime: 10 * time.Millisecond}, nil)
+	p.Add(testProxy(2), &tester.Result{ExecTime: time.Second}, nil)
+
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		px, err := p.Next()
+		assert.NoError(t, err)
+		counts[px.ToString()]++
+	}
+	assert.True(t, counts["10.0.0.1:8080"] > counts["10.0.0.2:8080"], "faster proxy should be preferred: %v", counts)
+}
+
+func TestPool_Concurrency(t *testing.T) {
+	p := New(NewWeightedRandom())
+	for i := 1; i <= 50; i++ {
+		p.Add(testProxy(i), nil, nil)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				px, err := p.Next()
+				if err != nil {
+					continue
+				}
+				if (worker+j)%3 == 0 {
+					p.MarkFailed(px)
+				} else {
+					p.MarkSuccess(px, time.Duration(j)*time.Millisecond)
+				}
+				_ = p.Entries()
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.True(t, p.Len() <= 50)
+}
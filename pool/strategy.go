@@ -0,0 +1,136 @@
+package pool
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/alekc/proxy"
+)
+
+//Strategy selects a proxy from the list of candidates. Select is always called with the pool
+//lock held and a non empty candidate list, so implementations don't need their own locking.
+//The list is owned by the pool, it must not be modified or kept after Select returns.
+type Strategy interface {
+	Select(candidates []*Entry) *Entry
+}
+
+//StrategyFunc allows usage of ordinary functions as strategies
+type StrategyFunc func(candidates []*Entry) *Entry
+
+//Select implements Strategy
+func (f StrategyFunc) Select(candidates []*Entry) *Entry {
+	return f(candidates)
+}
+
+//RoundRobin returns candidates one after another
+type RoundRobin struct {
+	next int
+}
+
+//NewRoundRobin creates round robin strategy
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{}
+}
+
+//Select implements Strategy
+func (rr *RoundRobin) Select(candidates []*Entry) *Entry {
+	if rr.next >= len(candidates) {
+		rr.next = 0
+	}
+	entry := candidates[rr.next]
+	rr.next++
+	return entry
+}
+
+//WeightedRandom picks a random candidate, faster proxies (lower latency) are picked more often
+type WeightedRandom struct {
+	rnd *rand.Rand
+}
+
+//NewWeightedRandom creates weighted random strategy
+func NewWeightedRandom() *WeightedRandom {
+	return &WeightedRandom{rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+//Select implements Strategy
+func (wr *WeightedRandom) Select(candidates []*Entry) *Entry {
+	//proxies without known latency are treated as if they had an average one
+	var known, sum time.Duration
+	for _, entry := range candidates {
+		if entry.Latency > 0 {
+			sum += entry.Latency
+			known++
+		}
+	}
+	average := time.Second
+	if known > 0 {
+		average = sum / known
+	}
+
+	weight := func(entry *Entry) float64 {
+		if entry.Latency <= 0 {
+			return 1 / average.Seconds()
+		}
+		return 1 / entry.Latency.Seconds()
+	}
+	total := 0.0
+	for _, entry := range candidates {
+		total += weight(entry)
+	}
+
+	point := wr.rnd.Float64() * total
+	for _, entry := range candidates {
+		if point < weight(entry) {
+			return entry
+		}
+		point -= weight(entry)
+	}
+	return candidates[len(candidates)-1]
+}
+
+//LeastRecentlyUsed returns the candidate which hasn't been used for the longest time
+func LeastRecentlyUsed() Strategy {
+	return StrategyFunc(func(candidates []*Entry) *Entry {
+		selected := candidates[0]
+		for _, entry := range candidates[1:] {
+			if entry.LastUsed.Before(selected.LastUsed) {
+				selected = entry
+			}
+		}
+		return selected
+	})
+}
+
+//Filter decides if the entry can be returned by the pool
+type Filter func(entry *Entry) bool
+
+//MinAnonymity accepts only proxies with known judgement and anonymity level at least equal to level
+func MinAnonymity(level proxy.AnonymityLevel) Filter {
+	return func(entry *Entry) bool {
+		return entry.Judgement != nil && entry.Judgement.AnonType.AtLeast(level)
+	}
+}
+
+//Country accepts only proxies located in one of given countries (iso codes, case insensitive)
+func Country(countries ...string) Filter {
+	allowed := make(map[string]bool, len(countries))
+	for _, country := range countries {
+		allowed[strings.ToUpper(country)] = true
+	}
+	return func(entry *Entry) bool {
+		return entry.Judgement != nil && allowed[strings.ToUpper(entry.Judgement.Country)]
+	}
+}
+
+//Type accepts only proxies of given types
+func Type(types ...proxy.ProxyType) Filter {
+	return func(entry *Entry) bool {
+		for _, t := range types {
+			if entry.Proxy.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
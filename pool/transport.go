@@ -0,0 +1,219 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/proxy/tester"
+)
+
+//DefaultMaxAttempts is the number of proxies tried by Transport for idempotent requests
+const DefaultMaxAttempts = 3
+
+//ProxyFunc returns a function usable as http.Transport.Proxy, every request goes through
+//the next proxy of the pool
+func (p *Pool) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	return func(*http.Request) (*url.URL, error) {
+		px, err := p.Next()
+		if err != nil {
+			return nil, err
+		}
+		return tester.ProxyURL(px)
+	}
+}
+
+//Transport is a http.RoundTripper sending requests through proxies of the pool. Idempotent
+//requests are retried through the next proxy on connection errors (including failed CONNECT to https
+//targets), when the proxy requires authentication (407) or when the proxy itself answers with 502, 503
+//or 504. Failures are reported to the pool, so proxies which keep failing are evicted. Other responses
+//(i.e. 5xx of the target server) are returned as they are.
+type Transport struct {
+	Pool *Pool
+	//Maximum number of proxies tried for a single idempotent request
+	MaxAttempts int
+	//NewTransport creates underlying transport for a proxy url. If nil, a transport with
+	//sane timeouts is used.
+	NewTransport func(proxyURL *url.URL) *http.Transport
+
+	mu         sync.Mutex
+	transports map[string]*http.Transport
+}
+
+//NewTransport creates rotating transport over the pool
+func NewTransport(pool *Pool) *Transport {
+	return &Transport{
+		Pool:        pool,
+		MaxAttempts: DefaultMaxAttempts,
+	}
+}
+
+//proxyFailureCodes are status codes which may be generated by the proxy itself
+var proxyFailureCodes = map[int]bool{
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+//proxyHeaders are response headers added by proxies to the responses they generate
+var proxyHeaders = []string{"Proxy-Status", "X-Squid-Error", "Via"}
+
+//isProxyFailure returns true if the response is an error generated by the proxy and not
+//by the target server
+func isProxyFailure(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusProxyAuthRequired {
+		return true
+	}
+	if !proxyFailureCodes[resp.StatusCode] {
+		return false
+	}
+	for _, h := range proxyHeaders {
+		if resp.Header.Get(h) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+//RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := t.MaxAttempts
+	if attempts <= 0 || !isRetryable(req) {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		px, err := t.Pool.Next()
+		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, err
+		}
+		transport, err := t.transportFor(px)
+		if err != nil {
+			t.Pool.Remove(px)
+			lastErr = err
+			continue
+		}
+
+		outReq := req
+		if attempt > 0 {
+			if outReq, err = rewindRequest(req); err != nil {
+				return nil, err
+			}
+		}
+
+		start := time.Now()
+		resp, err := transport.RoundTrip(outReq)
+		if err == nil && !isProxyFailure(resp) {
+			t.Pool.MarkSuccess(px, time.Since(start))
+			return resp, nil
+		}
+
+		if t.Pool.MarkFailed(px) {
+			t.closeTransport(px)
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("proxy %s: %v", px.URLString(false), err)
+			continue
+		}
+		//last attempt, return proxy response as is
+		if attempt == attempts-1 {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		lastErr = fmt.Errorf("proxy %s: %s", px.URLString(false), resp.Status)
+	}
+	return nil, lastErr
+}
+
+//transportFor returns cached transport for the proxy
+func (t *Transport) transportFor(px *proxy.Proxy) (*http.Transport, error) {
+	proxyURL, err := tester.ProxyURL(px)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.transports == nil {
+		t.transports = make(map[string]*http.Transport)
+	}
+	k := px.Key()
+	if transport, ok := t.transports[k]; ok {
+		return transport, nil
+	}
+
+	var transport *http.Transport
+	if t.NewTransport != nil {
+		transport = t.NewTransport(proxyURL)
+	} else {
+		transport = &http.Transport{
+			Proxy: http.ProxyURL(proxyURL),
+			DialContext: (&net.Dialer{
+				Timeout:   10 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 30 * time.Second,
+			IdleConnTimeout:       90 * time.Second,
+			MaxIdleConnsPerHost:   2,
+		}
+	}
+	t.transports[k] = transport
+	return transport, nil
+}
+
+//closeTransport releases connections of an evicted proxy
+func (t *Transport) closeTransport(px *proxy.Proxy) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	k := px.Key()
+	if transport, ok := t.transports[k]; ok {
+		transport.CloseIdleConnections()
+		delete(t.transports, k)
+	}
+}
+
+//CloseIdleConnections closes idle connections of all underlying transports
+func (t *Transport) CloseIdleConnections() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, transport := range t.transports {
+		transport.CloseIdleConnections()
+	}
+}
+
+//isRetryable returns true for idempotent requests whose body can be sent again
+func isRetryable(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+//rewindRequest returns a copy of the request with a fresh body
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body can't be rewound")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	newReq := *req
+	newReq.Body = body
+	return &newReq, nil
+}
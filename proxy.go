@@ -3,23 +3,68 @@ package proxy
 import (
 	"errors"
 	"fmt"
+	"net"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 var proxyRegex = regexp.MustCompile(`^((?:(?:25[0-5]|2[0-4][0-9]|1[0-9][0-9]|[1-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1[0-9][0-9]|[1-9]?[0-9])):(\d{1,5})$`)
 
-//Proxy represent an proxy object containing ip, port, and the proxy type
+//hostnameRegex validates a dns name (rfc 1123 labels separated by dots)
+var hostnameRegex = regexp.MustCompile(`^(?i:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?)(?:\.(?i:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?))*$`)
+var numericRegex = regexp.MustCompile(`^[0-9]+$`)
+
+//Proxy represent an proxy object containing ip (or hostname), port, and the proxy type
 //easyjson:json
 type Proxy struct {
-	IP   string `json:"ip"`
-	Port int    `json:"port"`
-	Type int    `json:"type,omitempty"`
+	//Parsed ip address of the proxy. Nil if proxy has been defined by hostname
+	IP net.IP `json:"ip,omitempty"`
+	//Hostname of the proxy. Empty if proxy has been defined by ip address
+	Host string    `json:"host,omitempty"`
+	Port int       `json:"port"`
+	Type ProxyType `json:"type,omitempty"`
+	//Optional credentials used for proxy authentication. Password is never
+	//included in the json output unless explicitly requested (see MarshalJSONWithCredentials)
+	Username string `json:"username,omitempty"`
+	Password string `json:"-"`
+	//Url or name of the list the proxy has been obtained from
+	Source string `json:"source,omitempty"`
+}
+
+//proxyWithCredentials is used to (un)serialize a proxy including its password
+type proxyWithCredentials struct {
+	Proxy
+	Password string `json:"password,omitempty"`
+}
+
+//MarshalJSONWithCredentials works like MarshalJSON but includes the proxy password in the output
+func (v Proxy) MarshalJSONWithCredentials() ([]byte, error) {
+	return proxyWithCredentials{Proxy: v, Password: v.Password}.MarshalJSON()
+}
+
+//UnmarshalJSONWithCredentials works like UnmarshalJSON but reads the proxy password as well
+func (v *Proxy) UnmarshalJSONWithCredentials(data []byte) error {
+	wrapper := proxyWithCredentials{}
+	if err := wrapper.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*v = wrapper.Proxy
+	v.Password = wrapper.Password
+	return nil
 }
 
-//ToString converts proxy to ip:port format
+//Hostname returns ip address or host name of the proxy (without brackets for ipv6)
+func (v *Proxy) Hostname() string {
+	if v.IP != nil {
+		return v.IP.String()
+	}
+	return v.Host
+}
+
+//ToString converts proxy to ip:port format. Ipv6 addresses are enclosed in brackets ([::1]:8080)
 func (v *Proxy) ToString() string {
-	return fmt.Sprintf("%s:%d", v.IP, v.Port)
+	return net.JoinHostPort(v.Hostname(), strconv.Itoa(v.Port))
 }
 
 //FromIpv4String converts ipv4 string (xxx.xx.xxx.xxx:xxxxx) to a Proxy object
@@ -36,13 +81,64 @@ func FromIpv4String(proxyString string) (*Proxy, error) {
 
 	//convert port to appropriate type
 	port, _ := strconv.Atoi(matchResult[0][2])
-	if !(port > 0 && port <= 65535) {
+	if !validPort(port) {
 		return nil, errors.New("invalid port")
 	}
 	px := &Proxy{
-		IP:   matchResult[0][1],
+		IP:   net.ParseIP(matchResult[0][1]),
 		Port: port,
-		Type: 0,
+		Type: TypeUnknown,
 	}
 	return px, nil
 }
+
+//Parse converts host:port string to a Proxy object. Host can be an ipv4 address,
+//an ipv6 address enclosed in brackets ([::1]:8080) or a hostname.
+func Parse(proxyString string) (*Proxy, error) {
+	proxyString = strings.TrimSpace(proxyString)
+	if proxyString == "" {
+		return nil, errors.New("input string is empty")
+	}
+
+	host, portString, err := net.SplitHostPort(proxyString)
+	if err != nil {
+		return nil, fmt.Errorf("this is not a valid host:port pair: %s", proxyString)
+	}
+
+	port, err := strconv.Atoi(portString)
+	if err != nil || !validPort(port) {
+		return nil, errors.New("invalid port")
+	}
+
+	px := &Proxy{Port: port}
+	if err := px.setHost(host); err != nil {
+		return nil, err
+	}
+	return px, nil
+}
+
+//setHost assigns either ip or hostname, depending on the content of host
+func (v *Proxy) setHost(host string) error {
+	if host == "" {
+		return errors.New("host is empty")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		v.IP = ip
+		v.Host = ""
+		return nil
+	}
+	//zoned ipv6 addresses and similar are not supported. Names with a numeric
+	//last label (i.e. 260.1.1.1) are malformed ip addresses rather than hostnames
+	host = strings.TrimSuffix(host, ".")
+	labels := strings.Split(host, ".")
+	if !hostnameRegex.MatchString(host) || len(host) > 253 || numericRegex.MatchString(labels[len(labels)-1]) {
+		return fmt.Errorf("invalid host: %s", host)
+	}
+	v.IP = nil
+	v.Host = strings.ToLower(host)
+	return nil
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
@@ -17,7 +17,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjson6fc2bfdaDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *Proxy) {
+func easyjson6fc2bfdaDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *proxyWithCredentials) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -36,12 +36,24 @@ func easyjson6fc2bfdaDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *Proxy) {
 			continue
 		}
 		switch key {
+		case "password":
+			out.Password = string(in.String())
 		case "ip":
-			out.IP = string(in.String())
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.IP).UnmarshalText(data))
+			}
+		case "host":
+			out.Host = string(in.String())
 		case "port":
 			out.Port = int(in.Int())
 		case "type":
-			out.Type = int(in.Int())
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Type).UnmarshalJSON(data))
+			}
+		case "username":
+			out.Username = string(in.String())
+		case "source":
+			out.Source = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -52,11 +64,156 @@ func easyjson6fc2bfdaDecodeGithubComAlekcProxy(in *jlexer.Lexer, out *Proxy) {
 		in.Consumed()
 	}
 }
-func easyjson6fc2bfdaEncodeGithubComAlekcProxy(out *jwriter.Writer, in Proxy) {
+func easyjson6fc2bfdaEncodeGithubComAlekcProxy(out *jwriter.Writer, in proxyWithCredentials) {
 	out.RawByte('{')
 	first := true
 	_ = first
+	if in.Password != "" {
+		const prefix string = ",\"password\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Password))
+	}
+	if len(in.IP) != 0 {
+		const prefix string = ",\"ip\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.RawText((in.IP).MarshalText())
+	}
+	if in.Host != "" {
+		const prefix string = ",\"host\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Host))
+	}
 	{
+		const prefix string = ",\"port\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Port))
+	}
+	if in.Type != 0 {
+		const prefix string = ",\"type\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.RawText((in.Type).MarshalText())
+	}
+	if in.Username != "" {
+		const prefix string = ",\"username\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Username))
+	}
+	if in.Source != "" {
+		const prefix string = ",\"source\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Source))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v proxyWithCredentials) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6fc2bfdaEncodeGithubComAlekcProxy(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v proxyWithCredentials) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6fc2bfdaEncodeGithubComAlekcProxy(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *proxyWithCredentials) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6fc2bfdaDecodeGithubComAlekcProxy(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *proxyWithCredentials) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6fc2bfdaDecodeGithubComAlekcProxy(l, v)
+}
+func easyjson6fc2bfdaDecodeGithubComAlekcProxy1(in *jlexer.Lexer, out *Proxy) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "ip":
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.IP).UnmarshalText(data))
+			}
+		case "host":
+			out.Host = string(in.String())
+		case "port":
+			out.Port = int(in.Int())
+		case "type":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Type).UnmarshalJSON(data))
+			}
+		case "username":
+			out.Username = string(in.String())
+		case "source":
+			out.Source = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6fc2bfdaEncodeGithubComAlekcProxy1(out *jwriter.Writer, in Proxy) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.IP) != 0 {
 		const prefix string = ",\"ip\":"
 		if first {
 			first = false
@@ -64,7 +221,17 @@ func easyjson6fc2bfdaEncodeGithubComAlekcProxy(out *jwriter.Writer, in Proxy) {
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.IP))
+		out.RawText((in.IP).MarshalText())
+	}
+	if in.Host != "" {
+		const prefix string = ",\"host\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Host))
 	}
 	{
 		const prefix string = ",\"port\":"
@@ -84,7 +251,27 @@ func easyjson6fc2bfdaEncodeGithubComAlekcProxy(out *jwriter.Writer, in Proxy) {
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int(int(in.Type))
+		out.RawText((in.Type).MarshalText())
+	}
+	if in.Username != "" {
+		const prefix string = ",\"username\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Username))
+	}
+	if in.Source != "" {
+		const prefix string = ",\"source\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Source))
 	}
 	out.RawByte('}')
 }
@@ -92,23 +279,23 @@ func easyjson6fc2bfdaEncodeGithubComAlekcProxy(out *jwriter.Writer, in Proxy) {
 // MarshalJSON supports json.Marshaler interface
 func (v Proxy) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6fc2bfdaEncodeGithubComAlekcProxy(&w, v)
+	easyjson6fc2bfdaEncodeGithubComAlekcProxy1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Proxy) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6fc2bfdaEncodeGithubComAlekcProxy(w, v)
+	easyjson6fc2bfdaEncodeGithubComAlekcProxy1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Proxy) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6fc2bfdaDecodeGithubComAlekcProxy(&r, v)
+	easyjson6fc2bfdaDecodeGithubComAlekcProxy1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Proxy) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6fc2bfdaDecodeGithubComAlekcProxy(l, v)
+	easyjson6fc2bfdaDecodeGithubComAlekcProxy1(l, v)
 }
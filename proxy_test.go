@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,14 +21,62 @@ func TestFromIpv4String(t *testing.T) {
 
 	px, err := FromIpv4String("1.2.3.4:567")
 	assert.NoError(t, err, "ip shouldn't return an error")
-	assert.Equal(t, &Proxy{"1.2.3.4", 567, 0}, px, "result unexpected")
+	assert.Equal(t, &Proxy{IP: net.ParseIP("1.2.3.4"), Port: 567}, px, "result unexpected")
 
 }
 
+func TestParse(t *testing.T) {
+	_, err := Parse("")
+	assert.EqualError(t, err, "input string is empty", "empty address should return an error")
+
+	for _, v := range []string{"260.1.1.1:123", "invalid string", "::1:8080", "1.2.3.4", "-bad-.com:80", "[fe80::1%eth0]:80"} {
+		_, err = Parse(v)
+		assert.Error(t, err, "%s should not be parsed", v)
+	}
+	for _, v := range []string{"1.2.3.4:0", "1.2.3.4:66000", "[::1]:abc"} {
+		_, err = Parse(v)
+		assert.EqualError(t, err, "invalid port", "port should be between 1 and 65535")
+	}
+
+	tests := []struct {
+		input    string
+		expected *Proxy
+		str      string
+	}{
+		{"1.2.3.4:567", &Proxy{IP: net.ParseIP("1.2.3.4"), Port: 567}, "1.2.3.4:567"},
+		{"[2001:db8::1]:8080", &Proxy{IP: net.ParseIP("2001:db8::1"), Port: 8080}, "[2001:db8::1]:8080"},
+		{"[::ffff:1.2.3.4]:80", &Proxy{IP: net.ParseIP("1.2.3.4"), Port: 80}, "1.2.3.4:80"},
+		{" Proxy.Example.com:3128 ", &Proxy{Host: "proxy.example.com", Port: 3128}, "proxy.example.com:3128"},
+	}
+	for _, test := range tests {
+		px, err := Parse(test.input)
+		assert.NoError(t, err, "%s should be parsed", test.input)
+		assert.Equal(t, test.expected, px, "result unexpected")
+		assert.Equal(t, test.str, px.ToString(), "unexpected string representation")
+
+		//round trip through string and json
+		again, err := Parse(px.ToString())
+		assert.NoError(t, err)
+		assert.Equal(t, px, again)
+
+		encoded, err := px.MarshalJSON()
+		assert.NoError(t, err)
+		decoded := &Proxy{}
+		assert.NoError(t, decoded.UnmarshalJSON(encoded))
+		assert.Equal(t, px, decoded, "json round trip failed for %s", encoded)
+	}
+}
+
 func TestProxy_ToString(t *testing.T) {
 	px := &Proxy{
-		IP:   "1.2.3.4",
+		IP:   net.ParseIP("1.2.3.4"),
 		Port: 1234,
 	}
 	assert.Equal(t, "1.2.3.4:1234", px.ToString(), "result should be 1.2.3.4:1234")
+
+	px = &Proxy{
+		IP:   net.ParseIP("::1"),
+		Port: 1234,
+	}
+	assert.Equal(t, "[::1]:1234", px.ToString(), "ipv6 address should be enclosed in brackets")
 }
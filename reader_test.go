@@ -0,0 +1,97 @@
+package proxy
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListReader_Plain(t *testing.T) {
+	list := "\ufeff# comment line\r\n" +
+		"1.2.3.4:8080\r\n" +
+		"\r\n" +
+		"[2001:db8::1]:3128\n" +
+		"5.6.7.8:1080:user:pa:ss\n" +
+		"user:pass@proxy.example.com:3128\n" +
+		"socks5://9.9.9.9:1080\n" +
+		"260.1.1.1:80\n" +
+		"// another comment\n" +
+		"[2001:db8::2]:8080:user:pass\n"
+
+	reader := NewListReader(strings.NewReader(list), FormatAuto)
+	reader.DefaultType = TypeHTTP
+	proxies, errs := reader.ReadAll()
+	assert.Equal(t, FormatPlain, reader.Format, "format should be detected")
+	assert.Equal(t, []*Proxy{
+		{IP: net.ParseIP("1.2.3.4"), Port: 8080, Type: TypeHTTP},
+		{IP: net.ParseIP("2001:db8::1"), Port: 3128, Type: TypeHTTP},
+		{IP: net.ParseIP("5.6.7.8"), Port: 1080, Type: TypeHTTP, Username: "user", Password: "pa:ss"},
+		{Host: "proxy.example.com", Port: 3128, Type: TypeHTTP, Username: "user", Password: "pass"},
+		{IP: net.ParseIP("9.9.9.9"), Port: 1080, Type: TypeSocks5},
+		{IP: net.ParseIP("2001:db8::2"), Port: 8080, Type: TypeHTTP, Username: "user", Password: "pass"},
+	}, proxies)
+
+	if assert.Len(t, errs, 1) {
+		lineErr, ok := errs[0].(*LineError)
+		assert.True(t, ok, "error should be a LineError")
+		assert.Equal(t, 8, lineErr.Line)
+		assert.Equal(t, "260.1.1.1:80", lineErr.Text)
+	}
+}
+
+func TestListReader_LongLine(t *testing.T) {
+	list := "1.2.3.4:8080\n" + strings.Repeat("x", maxLineLength+1) + "\n5.6.7.8:3128"
+	proxies, errs := NewListReader(strings.NewReader(list), FormatPlain).ReadAll()
+	assert.Equal(t, []*Proxy{
+		{IP: net.ParseIP("1.2.3.4"), Port: 8080},
+		{IP: net.ParseIP("5.6.7.8"), Port: 3128},
+	}, proxies)
+	if assert.Len(t, errs, 1) {
+		lineErr, ok := errs[0].(*LineError)
+		assert.True(t, ok, "error should be a LineError")
+		assert.Equal(t, 2, lineErr.Line)
+	}
+}
+
+func TestListReader_CSV(t *testing.T) {
+	list := "IP;Port;Protocol;User;Password\n" +
+		"1.2.3.4;8080;http;;\n" +
+		"2001:db8::1;1080;socks5;user;pass\n" +
+		"1.2.3.5;;http;;\n" +
+		"1.2.3.6;3128;ftp;;\n"
+
+	proxies, errs := NewListReader(strings.NewReader(list), FormatAuto).ReadAll()
+	assert.Equal(t, []*Proxy{
+		{IP: net.ParseIP("1.2.3.4"), Port: 8080, Type: TypeHTTP},
+		{IP: net.ParseIP("2001:db8::1"), Port: 1080, Type: TypeSocks5, Username: "user", Password: "pass"},
+	}, proxies)
+	assert.Len(t, errs, 2)
+
+	list = "proxy,country\nsocks4://1.2.3.4:1080,DE\n"
+	proxies, errs = NewListReader(strings.NewReader(list), FormatCSV).ReadAll()
+	assert.Empty(t, errs)
+	assert.Equal(t, []*Proxy{{IP: net.ParseIP("1.2.3.4"), Port: 1080, Type: TypeSocks4}}, proxies)
+}
+
+func TestListReader_JSON(t *testing.T) {
+	list := `{"ip":"1.2.3.4","port":8080,"type":"http"}` + "\n" +
+		`{"host":"proxy.example.com","port":1080,"type":4,"username":"user","password":"pass"}` + "\n" +
+		`{"port":1080}` + "\n" +
+		`not json` + "\n" +
+		`{"ip":"1.2.3.5","port":0}` + "\n" +
+		`{"ip":"1.2.3.5","port":99999}` + "\n" +
+		`{"ip":"1.2.3.5","port":-1}` + "\n"
+
+	proxies, errs := NewListReader(strings.NewReader(list), FormatAuto).ReadAll()
+	assert.Equal(t, []*Proxy{
+		{IP: net.ParseIP("1.2.3.4"), Port: 8080, Type: TypeHTTP},
+		{Host: "proxy.example.com", Port: 1080, Type: TypeSocks5, Username: "user", Password: "pass"},
+	}, proxies)
+	if assert.Len(t, errs, 5) {
+		for _, err := range errs[2:] {
+			assert.EqualError(t, err.(*LineError).Err, "invalid port")
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/proxy/tester"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+//Provenance describes when a proxy has been seen in a source
+type Provenance struct {
+	Source    string
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+//Stats contains statistics of a single source
+type Stats struct {
+	Source    string
+	Fetches   int
+	Failures  int
+	LastFetch time.Time
+	LastError string
+	//Proxies found during the last fetch
+	LastFound int
+	//Lines which couldn't be parsed during the last fetch
+	LastInvalid int
+	//Number of distinct proxies ever provided by the source
+	Distinct int
+	//Number of proxies checked and found working (see Registry.Check)
+	Checked int
+	Working int
+}
+
+//Yield returns percentage (0..100) of checked proxies which were working
+func (s *Stats) Yield() float64 {
+	if s.Checked == 0 {
+		return 0
+	}
+	return float64(s.Working) * 100 / float64(s.Checked)
+}
+
+//Registry manages proxy sources, refreshes them periodically and keeps track of provenance of every proxy.
+//All methods are safe for concurrent use.
+type Registry struct {
+	//Client used for url sources
+	Client *http.Client
+	//OnFetch is called after every successful refresh of a source with the proxies it contains
+	OnFetch func(source *Source, proxies []*proxy.Proxy)
+
+	mu         sync.Mutex
+	sources    map[string]*Source
+	stats      map[string]*Stats
+	provenance map[string]map[string]*Provenance
+	//proxies already checked for a given source, so the stats count distinct proxies
+	checked map[string]map[string]bool
+	logger  *logrus.Logger
+	now     func() time.Time
+}
+
+//NewRegistry creates new registry containing given sources
+func NewRegistry(sources ...*Source) (*Registry, error) {
+	r := &Registry{
+		Client:     &http.Client{Timeout: DefaultTimeout},
+		sources:    make(map[string]*Source),
+		stats:      make(map[string]*Stats),
+		provenance: make(map[string]map[string]*Provenance),
+		checked:    make(map[string]map[string]bool),
+		now:        time.Now,
+	}
+	//default logger (only errors are visible)
+	r.logger = logrus.New()
+	r.logger.Out = os.Stdout
+	r.logger.SetLevel(logrus.ErrorLevel)
+
+	for _, source := range sources {
+		if err := r.Add(source); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+//SetLogger sets the logger used by the registry
+func (r *Registry) SetLogger(log *logrus.Logger) {
+	r.logger = log
+}
+
+//Add registers a new source
+func (r *Registry) Add(source *Source) error {
+	if err := source.Validate(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sources[source.Name]; ok {
+		return errors.Errorf("duplicate source name %s", source.Name)
+	}
+	r.sources[source.Name] = source
+	r.stats[source.Name] = &Stats{Source: source.Name}
+	return nil
+}
+
+//Sources returns all registered sources sorted by name
+func (r *Registry) Sources() []*Source {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]*Source, 0, len(r.sources))
+	for _, source := range r.sources {
+		result = append(result, source)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+//Refresh fetches and parses the source, updating provenance of all proxies found.
+func (r *Registry) Refresh(ctx context.Context, name string) ([]*proxy.Proxy, error) {
+	r.mu.Lock()
+	source, ok := r.sources[name]
+	r.mu.Unlock()
+	if !ok {
+		return nil, errors.Errorf("unknown source %s", name)
+	}
+
+	content, err := source.fetch(ctx, r.Client)
+	now := r.now()
+
+	r.mu.Lock()
+	stats := r.stats[name]
+	stats.Fetches++
+	stats.LastFetch = now
+	if err != nil {
+		stats.Failures++
+		stats.LastError = err.Error()
+		r.mu.Unlock()
+		r.logger.
+			WithError(err).
+			WithField("source", name).
+			Error("couldn't fetch source")
+		return nil, err
+	}
+
+	proxies, invalid := source.parse(content)
+	stats.LastError = ""
+	stats.LastFound = len(proxies)
+	stats.LastInvalid = invalid
+	for _, px := range proxies {
+		k := px.Key()
+		seen, ok := r.provenance[k]
+		if !ok {
+			seen = make(map[string]*Provenance)
+			r.provenance[k] = seen
+		}
+		if p, ok := seen[name]; ok {
+			p.LastSeen = now
+		} else {
+			seen[name] = &Provenance{Source: name, FirstSeen: now, LastSeen: now}
+			stats.Distinct++
+		}
+	}
+	r.mu.Unlock()
+
+	r.logger.
+		WithField("source", name).
+		WithField("found", len(proxies)).
+		WithField("invalid", invalid).
+		Info("source refreshed")
+	if r.OnFetch != nil {
+		r.OnFetch(source, proxies)
+	}
+	return proxies, nil
+}
+
+//Run refreshes all enabled sources according to their intervals until the context is cancelled.
+//Sources are taken when Run is called, sources added or enabled later are not refreshed
+//until Run is started again.
+func (r *Registry) Run(ctx context.Context) {
+	wg := sync.WaitGroup{}
+	for _, source := range r.Sources() {
+		if !source.Enabled {
+			continue
+		}
+		wg.Add(1)
+		go func(source *Source) {
+			defer wg.Done()
+			ticker := time.NewTicker(source.refreshInterval())
+			defer ticker.Stop()
+			for {
+				_, _ = r.Refresh(ctx, source.Name)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(source)
+	}
+	wg.Wait()
+}
+
+//Provenance returns all sources which provided the proxy
+func (r *Registry) Provenance(px *proxy.Proxy) []Provenance {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]Provenance, 0)
+	for _, p := range r.provenance[px.Key()] {
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FirstSeen.Before(result[j].FirstSeen) ||
+			(result[i].FirstSeen.Equal(result[j].FirstSeen) && result[i].Source < result[j].Source)
+	})
+	return result
+}
+
+//RecordCheck attributes the check result to all sources which provided the proxy.
+//Every proxy is counted only once per source, a later check overrides the previous result.
+func (r *Registry) RecordCheck(px *proxy.Proxy, result *tester.Result) {
+	working := result != nil && result.Ok
+	k := px.Key()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name := range r.provenance[k] {
+		stats := r.stats[name]
+		if stats == nil {
+			continue
+		}
+		checked := r.checked[name]
+		if checked == nil {
+			checked = make(map[string]bool)
+			r.checked[name] = checked
+		}
+		if previous, ok := checked[k]; ok {
+			if previous {
+				stats.Working--
+			}
+		} else {
+			stats.Checked++
+		}
+		checked[k] = working
+		if working {
+			stats.Working++
+		}
+	}
+}
+
+//Check checks the proxy with the tester and records the result (see RecordCheck)
+func (r *Registry) Check(ts *tester.Tester, px *proxy.Proxy) (*tester.Result, error) {
+	result, err := ts.CheckProxy(px)
+	if result != nil {
+		r.RecordCheck(px, result)
+	}
+	return result, err
+}
+
+//Stats returns statistics of all sources sorted by name
+func (r *Registry) Stats() []Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]Stats, 0, len(r.stats))
+	for _, stats := range r.stats {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Source < result[j].Source
+	})
+	return result
+}
@@ -0,0 +1,197 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/proxy/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_RefreshURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# list\n1.2.3.4:8080\nbroken line\nsocks5://5.6.7.8:1080\n")
+	}))
+	defer srv.Close()
+
+	registry, err := NewRegistry(&Source{Name: "web", Kind: KindURL, Location: srv.URL, Format: "plain"})
+	assert.NoError(t, err)
+	proxies, err := registry.Refresh(context.Background(), "web")
+	assert.NoError(t, err)
+	if assert.Len(t, proxies, 2) {
+		assert.Equal(t, "1.2.3.4:8080", proxies[0].ToString())
+		assert.Equal(t, "web", proxies[0].Source)
+		assert.Equal(t, proxy.TypeSocks5, proxies[1].Type)
+	}
+
+	stats := registry.Stats()
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, 1, stats[0].Fetches)
+		assert.Equal(t, 2, stats[0].LastFound)
+		assert.Equal(t, 1, stats[0].LastInvalid)
+		assert.Equal(t, 2, stats[0].Distinct)
+	}
+}
+
+func TestSource_ParseExtract(t *testing.T) {
+	source := &Source{Name: "page", Format: FormatExtract, DefaultType: proxy.TypeSocks5}
+	proxies, invalid := source.parse([]byte("<td>1.2.3.4</td><td>1080</td> http://5.6.7.8:3128"))
+	assert.Equal(t, 0, invalid)
+	if assert.Len(t, proxies, 2) {
+		assert.Equal(t, proxy.TypeSocks5, proxies[0].Type, "default type should be applied")
+		assert.Equal(t, proxy.TypeHTTP, proxies[1].Type, "scheme should win over the default type")
+		assert.Equal(t, "page", proxies[0].Source)
+	}
+}
+
+func TestRegistry_RefreshFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	registry, _ := NewRegistry(&Source{Name: "web", Kind: KindURL, Location: srv.URL})
+	_, err := registry.Refresh(context.Background(), "web")
+	assert.Error(t, err)
+	stats := registry.Stats()[0]
+	assert.Equal(t, 1, stats.Failures)
+	assert.Contains(t, stats.LastError, "404")
+
+	_, err = registry.Refresh(context.Background(), "unknown")
+	assert.Error(t, err)
+}
+
+func TestRegistry_RefreshCommandTimeout(t *testing.T) {
+	registry, err := NewRegistry(
+		&Source{Name: "slow", Kind: KindCommand, Location: "sleep", Args: []string{"5"}, Timeout: Duration(50 * time.Millisecond)},
+		&Source{Name: "endless", Kind: KindCommand, Location: "yes"},
+	)
+	assert.NoError(t, err)
+
+	start := time.Now()
+	_, err = registry.Refresh(context.Background(), "slow")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "timed out")
+	}
+	assert.True(t, time.Since(start) < 5*time.Second, "command should be killed on timeout")
+
+	_, err = registry.Refresh(context.Background(), "endless")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "too big")
+	}
+}
+
+func TestRegistry_Provenance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sources")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "list.txt")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("1.2.3.4:8080\n"), 0600))
+
+	registry, err := NewRegistry(
+		&Source{Name: "file", Kind: KindFile, Location: path, DefaultType: proxy.TypeHTTP},
+		&Source{Name: "command", Kind: KindCommand, Location: "echo", Args: []string{"http://1.2.3.4:8080 9.9.9.9:3128"}, Format: FormatExtract},
+	)
+	assert.NoError(t, err)
+
+	start := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	registry.now = func() time.Time { return start }
+	_, err = registry.Refresh(context.Background(), "file")
+	assert.NoError(t, err)
+
+	registry.now = func() time.Time { return start.Add(time.Hour) }
+	_, err = registry.Refresh(context.Background(), "file")
+	assert.NoError(t, err)
+	proxies, err := registry.Refresh(context.Background(), "command")
+	assert.NoError(t, err)
+	assert.Len(t, proxies, 2)
+
+	px := &proxy.Proxy{IP: []byte{1, 2, 3, 4}, Port: 8080, Type: proxy.TypeHTTP}
+	provenance := registry.Provenance(px)
+	if assert.Len(t, provenance, 2) {
+		assert.Equal(t, "file", provenance[0].Source)
+		assert.Equal(t, start, provenance[0].FirstSeen)
+		assert.Equal(t, start.Add(time.Hour), provenance[0].LastSeen)
+		assert.Equal(t, "command", provenance[1].Source)
+	}
+
+	//check results are credited to every source, each proxy only once
+	registry.RecordCheck(px, &tester.Result{Ok: false})
+	registry.RecordCheck(px, &tester.Result{Ok: true})
+	registry.RecordCheck(&proxy.Proxy{IP: []byte{9, 9, 9, 9}, Port: 3128}, &tester.Result{Ok: false})
+	stats := registry.Stats()
+	assert.Equal(t, "command", stats[0].Source)
+	assert.Equal(t, 2, stats[0].Checked)
+	assert.Equal(t, 1, stats[0].Working)
+	assert.Equal(t, 50.0, stats[0].Yield())
+	assert.Equal(t, 1, stats[1].Checked)
+	assert.Equal(t, 100.0, stats[1].Yield())
+}
+
+func TestRegistry_Run(t *testing.T) {
+	fetched := make(chan []*proxy.Proxy, 10)
+	registry, _ := NewRegistry(
+		&Source{Name: "enabled", Kind: KindCommand, Location: "echo", Args: []string{"1.2.3.4:80"}, Enabled: true, Interval: Duration(time.Hour)},
+		&Source{Name: "disabled", Kind: KindCommand, Location: "echo", Args: []string{"5.6.7.8:80"}},
+	)
+	registry.OnFetch = func(source *Source, proxies []*proxy.Proxy) {
+		fetched <- proxies
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		registry.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case proxies := <-fetched:
+		assert.Len(t, proxies, 1)
+		assert.Equal(t, "enabled", proxies[0].Source)
+	case <-time.After(5 * time.Second):
+		t.Fatal("source wasn't refreshed")
+	}
+	cancel()
+	<-done
+	assert.Equal(t, 0, registry.Stats()[0].Fetches)
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sources")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sources.json")
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`[
+		{"name": "a", "kind": "url", "location": "http://example.com/list.txt", "format": "plain", "interval": "30m", "enabled": true},
+		{"name": "b", "kind": "file", "location": "/tmp/list.csv", "format": "csv", "default_type": "socks5"}
+	]`), 0600))
+	sources, err := LoadConfig(path)
+	assert.NoError(t, err)
+	if assert.Len(t, sources, 2) {
+		assert.Equal(t, 30*time.Minute, sources[0].refreshInterval())
+		assert.Equal(t, DefaultInterval, sources[1].refreshInterval())
+		assert.Equal(t, proxy.TypeSocks5, sources[1].DefaultType)
+	}
+
+	invalid := []string{
+		`[{"name": "a", "kind": "ftp", "location": "x"}]`,
+		`[{"name": "a", "kind": "url", "location": ""}]`,
+		`[{"name": "a", "kind": "url", "location": "x", "format": "xml"}]`,
+		`[{"name": "a", "kind": "url", "location": "x", "interval": "soon"}]`,
+		`[{"name": "a", "kind": "url", "location": "x"}, {"name": "a", "kind": "file", "location": "y"}]`,
+	}
+	for _, content := range invalid {
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+		_, err := LoadConfig(path)
+		assert.Error(t, err, content)
+	}
+}
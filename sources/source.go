@@ -0,0 +1,248 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/proxy/extract"
+	"github.com/pkg/errors"
+)
+
+//Kind defines where the source content comes from
+type Kind string
+
+//Known source kinds
+const (
+	KindURL     Kind = "url"
+	KindFile    Kind = "file"
+	KindCommand Kind = "command"
+)
+
+//FormatExtract scrapes proxies from arbitrary html or text (see extract package).
+//Any other format is a list format understood by proxy.ListReader.
+const FormatExtract = "extract"
+
+//maximum size of the source content
+const maxContentSize = 32 * 1024 * 1024
+
+//DefaultInterval is used for sources without a refresh interval
+const DefaultInterval = time.Hour
+
+//DefaultTimeout is used for sources without a fetch timeout
+const DefaultTimeout = 30 * time.Second
+
+//Duration is a time.Duration encoded in json as a string (i.e. "1h30m")
+type Duration time.Duration
+
+//MarshalJSON implements json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+//UnmarshalJSON implements json.Unmarshaler. Both strings ("10m") and nanoseconds are accepted.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case float64:
+		*d = Duration(v)
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*d = Duration(parsed)
+	default:
+		return errors.Errorf("invalid duration: %s", data)
+	}
+	return nil
+}
+
+//Source is a single proxy list source
+type Source struct {
+	//Unique name of the source
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+	//Url, file path or command (executable) depending on the kind
+	Location string `json:"location"`
+	//Arguments of the command
+	Args []string `json:"args,omitempty"`
+	//List format (auto, plain, url, csv, jsonl) or "extract"
+	Format string `json:"format"`
+	//Type assigned to proxies without an explicit type
+	DefaultType proxy.ProxyType `json:"default_type,omitempty"`
+	Interval    Duration        `json:"interval"`
+	//Maximum duration of a single fetch (DefaultTimeout if not set)
+	Timeout Duration `json:"timeout,omitempty"`
+	Enabled bool     `json:"enabled"`
+}
+
+//Validate checks source definition
+func (s *Source) Validate() error {
+	if s.Name == "" {
+		return errors.New("source name is empty")
+	}
+	if s.Location == "" {
+		return errors.Errorf("source %s: location is empty", s.Name)
+	}
+	switch s.Kind {
+	case KindURL, KindFile, KindCommand:
+	default:
+		return errors.Errorf("source %s: unknown kind %q", s.Name, s.Kind)
+	}
+	if s.Format != FormatExtract && s.Format != "" {
+		if _, err := proxy.ParseListFormat(s.Format); err != nil {
+			return errors.Wrapf(err, "source %s", s.Name)
+		}
+	}
+	if s.Interval < 0 {
+		return errors.Errorf("source %s: negative interval", s.Name)
+	}
+	if s.Timeout < 0 {
+		return errors.Errorf("source %s: negative timeout", s.Name)
+	}
+	return nil
+}
+
+//refreshInterval returns the interval with the default applied
+func (s *Source) refreshInterval() time.Duration {
+	if s.Interval <= 0 {
+		return DefaultInterval
+	}
+	return time.Duration(s.Interval)
+}
+
+//fetchTimeout returns the timeout with the default applied
+func (s *Source) fetchTimeout() time.Duration {
+	if s.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return time.Duration(s.Timeout)
+}
+
+//LoadConfig reads json array of sources from the file
+func LoadConfig(path string) ([]*Source, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]*Source, 0)
+	if err := json.Unmarshal(content, &sources); err != nil {
+		return nil, errors.Wrapf(err, "couldn't parse %s", path)
+	}
+	names := make(map[string]bool)
+	for _, source := range sources {
+		if err := source.Validate(); err != nil {
+			return nil, err
+		}
+		if names[source.Name] {
+			return nil, errors.Errorf("duplicate source name %s", source.Name)
+		}
+		names[source.Name] = true
+	}
+	return sources, nil
+}
+
+//fetch retrieves the content of the source
+func (s *Source) fetch(ctx context.Context, client *http.Client) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.fetchTimeout())
+	defer cancel()
+
+	switch s.Kind {
+	case KindURL:
+		req, err := http.NewRequest(http.MethodGet, s.Location, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+		return readLimited(resp.Body)
+	case KindFile:
+		file, err := os.Open(s.Location)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return readLimited(file)
+	case KindCommand:
+		stderr := &bytes.Buffer{}
+		cmd := exec.CommandContext(ctx, s.Location, s.Args...) // #nosec G204 - commands come from the operator config
+		cmd.Stderr = stderr
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		output, readErr := readLimited(stdout)
+		if readErr != nil {
+			//stop the command which keeps writing
+			cancel()
+		}
+		err = cmd.Wait()
+		switch {
+		case readErr != nil:
+			return nil, readErr
+		case ctx.Err() == context.DeadlineExceeded:
+			return nil, errors.Errorf("command timed out after %s", s.fetchTimeout())
+		case err != nil:
+			return nil, errors.Wrapf(err, "command failed: %s", strings.TrimSpace(stderr.String()))
+		}
+		return output, nil
+	}
+	return nil, errors.Errorf("unknown kind %q", s.Kind)
+}
+
+//parse converts the content of the source to proxies. Number of lines which couldn't be parsed is returned as well.
+func (s *Source) parse(content []byte) ([]*proxy.Proxy, int) {
+	if s.Format == FormatExtract {
+		proxies := extract.Extract(string(content), s.Name)
+		for _, px := range proxies {
+			if px.Type == proxy.TypeUnknown {
+				px.Type = s.DefaultType
+			}
+		}
+		return proxies, 0
+	}
+
+	format := proxy.FormatAuto
+	if s.Format != "" {
+		format, _ = proxy.ParseListFormat(s.Format)
+	}
+	reader := proxy.NewListReader(bytes.NewReader(content), format)
+	reader.DefaultType = s.DefaultType
+	proxies, lineErrors := reader.ReadAll()
+	for _, px := range proxies {
+		px.Source = s.Name
+	}
+	return proxies, len(lineErrors)
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	content, err := ioutil.ReadAll(io.LimitReader(r, maxContentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxContentSize {
+		return nil, errors.New("source content is too big")
+	}
+	return content, nil
+}
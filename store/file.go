@@ -0,0 +1,206 @@
+package store
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/proxy/tester"
+	"github.com/pkg/errors"
+)
+
+//FileStore is an embedded store keeping the history in an append only file (one json record
+//per line). The whole history is indexed in memory when the file is opened.
+//All methods are safe for concurrent use.
+type FileStore struct {
+	mu      sync.RWMutex
+	file    *os.File
+	writer  *bufio.Writer
+	history map[string][]*Record
+	//number of lines which couldn't be decoded on open (i.e. truncated by a crash)
+	skipped int
+}
+
+var _ Store = (*FileStore)(nil)
+var _ tester.Recorder = (*FileStore)(nil)
+
+//Open opens (or creates) file store at given path
+func Open(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	fs := &FileStore{
+		file:    file,
+		writer:  bufio.NewWriter(file),
+		history: make(map[string][]*Record),
+	}
+	if err := fs.load(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return fs, nil
+}
+
+//load reads existing records into memory
+func (fs *FileStore) load() error {
+	scanner := bufio.NewScanner(fs.file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := &Record{}
+		if err := record.UnmarshalJSON(scanner.Bytes()); err != nil {
+			fs.skipped++
+			continue
+		}
+		fs.index(record)
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "couldn't read store")
+	}
+	//make sure a truncated last line won't be merged with the next record
+	if info, err := fs.file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := fs.file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := fs.file.Write([]byte{'\n'}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (fs *FileStore) index(record *Record) {
+	k := record.Proxy.Key()
+	records := fs.history[k]
+	//keep records sorted, out of order records are rare
+	i := len(records)
+	for i > 0 && records[i-1].Time.After(record.Time) {
+		i--
+	}
+	records = append(records, nil)
+	copy(records[i+1:], records[i:])
+	records[i] = record
+	fs.history[k] = records
+}
+
+//Skipped returns the number of corrupted lines ignored when the store was opened
+func (fs *FileStore) Skipped() int {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.skipped
+}
+
+//Save implements Store
+func (fs *FileStore) Save(record *Record) error {
+	if record == nil {
+		return errors.New("record is nil")
+	}
+	encoded, err := record.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.file == nil {
+		return errors.New("store is closed")
+	}
+	if _, err = fs.writer.Write(append(encoded, '\n')); err != nil {
+		return err
+	}
+	if err = fs.writer.Flush(); err != nil {
+		return err
+	}
+	fs.index(record)
+	return nil
+}
+
+//Record implements tester.Recorder
+func (fs *FileStore) Record(px *proxy.Proxy, result *tester.Result, checkedAt time.Time) error {
+	return fs.Save(NewRecord(px, result, checkedAt))
+}
+
+//History implements Store
+func (fs *FileStore) History(px *proxy.Proxy, since time.Time) ([]*Record, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	records := fs.history[px.Key()]
+	start := sort.Search(len(records), func(i int) bool {
+		return !records[i].Time.Before(since)
+	})
+	result := make([]*Record, len(records)-start)
+	copy(result, records[start:])
+	return result, nil
+}
+
+//Latest implements Store. Records are sorted by time, newest first.
+func (fs *FileStore) Latest() ([]*Record, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	result := make([]*Record, 0, len(fs.history))
+	for _, records := range fs.history {
+		if len(records) > 0 {
+			result = append(result, records[len(records)-1])
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.After(result[j].Time)
+	})
+	return result, nil
+}
+
+//AliveSince implements Store
+func (fs *FileStore) AliveSince(since time.Time) ([]*proxy.Proxy, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	result := make([]*proxy.Proxy, 0)
+	for _, records := range fs.history {
+		for i := len(records) - 1; i >= 0 && !records[i].Time.Before(since); i-- {
+			if records[i].Ok {
+				px := records[i].Proxy
+				result = append(result, &px)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+//Uptime implements Store. Returns 0 if there are no records in the given period.
+func (fs *FileStore) Uptime(px *proxy.Proxy, since time.Time) (float64, error) {
+	records, err := fs.History(px, since)
+	if err != nil || len(records) == 0 {
+		return 0, err
+	}
+	ok := 0
+	for _, record := range records {
+		if record.Ok {
+			ok++
+		}
+	}
+	return float64(ok) * 100 / float64(len(records)), nil
+}
+
+//Close implements Store
+func (fs *FileStore) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.file == nil {
+		return nil
+	}
+	err := fs.writer.Flush()
+	if closeErr := fs.file.Close(); err == nil {
+		err = closeErr
+	}
+	fs.file = nil
+	return err
+}
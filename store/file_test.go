@@ -0,0 +1,95 @@
+package store
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/proxy/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history.jsonl")
+
+	fs, err := Open(path)
+	assert.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	alive := &proxy.Proxy{IP: net.ParseIP("1.2.3.4"), Port: 8080, Type: proxy.TypeHTTP}
+	dead := &proxy.Proxy{IP: net.ParseIP("2001:db8::1"), Port: 1080, Type: proxy.TypeSocks5}
+
+	okResult := &tester.Result{Ok: true, ResponseCode: 200, ExecTime: time.Second, Body: `{"anon_type":3,"country":"DE"}`}
+	failedResult := &tester.Result{Err: errors.New("dial tcp: connection refused")}
+
+	assert.NoError(t, fs.Record(alive, failedResult, now.Add(-48*time.Hour)))
+	assert.NoError(t, fs.Record(alive, okResult, now.Add(-2*time.Hour)))
+	assert.NoError(t, fs.Record(alive, failedResult, now.Add(-time.Hour)))
+	assert.NoError(t, fs.Record(alive, okResult, now.Add(-3*time.Hour))) //out of order
+	assert.NoError(t, fs.Record(dead, okResult, now.Add(-72*time.Hour)))
+	assert.NoError(t, fs.Record(dead, failedResult, now))
+	assert.NoError(t, fs.Close())
+
+	//reopen and check that everything has been persisted
+	fs, err = Open(path)
+	assert.NoError(t, err)
+	defer fs.Close()
+	assert.Equal(t, 0, fs.Skipped())
+
+	history, err := fs.History(alive, now.Add(-24*time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, history, 3) {
+		assert.True(t, history[0].Time.Equal(now.Add(-3*time.Hour)), "history should be sorted")
+		assert.Equal(t, proxy.AnonElite, history[0].Judgement.AnonType)
+		assert.Equal(t, tester.ErrorRefused, history[2].ErrorClass)
+	}
+
+	uptime, err := fs.Uptime(alive, now.Add(-24*time.Hour))
+	assert.NoError(t, err)
+	assert.InDelta(t, 66.66, uptime, 0.01)
+
+	aliveProxies, err := fs.AliveSince(now.Add(-24 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []*proxy.Proxy{alive}, aliveProxies)
+
+	latest, err := fs.Latest()
+	assert.NoError(t, err)
+	if assert.Len(t, latest, 2) {
+		assert.Equal(t, *dead, latest[0].Proxy)
+		assert.False(t, latest[0].Ok)
+		assert.True(t, latest[1].Time.Equal(now.Add(-time.Hour)))
+	}
+}
+
+func TestFileStore_Corrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history.jsonl")
+
+	content := `{"proxy":{"ip":"1.2.3.4","port":8080},"time":"2019-01-01T00:00:00Z","latency":0,"ok":true}` + "\n" +
+		`{"proxy":{"ip":"1.2.3.4","port":80`
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+	fs, err := Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fs.Skipped())
+	px := &proxy.Proxy{IP: net.ParseIP("1.2.3.4"), Port: 8080}
+	assert.NoError(t, fs.Record(px, &tester.Result{Ok: true}, time.Now()))
+	assert.NoError(t, fs.Close())
+
+	fs, err = Open(path)
+	assert.NoError(t, err)
+	defer fs.Close()
+	assert.Equal(t, 1, fs.Skipped(), "new records should not be merged with the corrupted line")
+	history, _ := fs.History(px, time.Time{})
+	assert.Len(t, history, 2)
+}
@@ -0,0 +1,21 @@
+package store
+
+import (
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/proxy/tester"
+)
+
+//Record is a single check of a proxy
+//easyjson:json
+type Record struct {
+	Proxy      proxy.Proxy       `json:"proxy"`
+	Time       time.Time         `json:"time"`
+	Latency    time.Duration     `json:"latency"`
+	Ok         bool              `json:"ok"`
+	StatusCode int               `json:"status_code,omitempty"`
+	ErrorClass tester.ErrorClass `json:"error_class,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Judgement  *proxy.Judgement  `json:"judgement,omitempty"`
+}
@@ -0,0 +1,187 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package store
+
+import (
+	json "encoding/json"
+	proxy "github.com/alekc/proxy"
+	tester "github.com/alekc/proxy/tester"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson15d5d517DecodeGithubComAlekcProxyStore(in *jlexer.Lexer, out *Record) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "proxy":
+			(out.Proxy).UnmarshalEasyJSON(in)
+		case "time":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Time).UnmarshalJSON(data))
+			}
+		case "latency":
+			out.Latency = time.Duration(in.Int64())
+		case "ok":
+			out.Ok = bool(in.Bool())
+		case "status_code":
+			out.StatusCode = int(in.Int())
+		case "error_class":
+			out.ErrorClass = tester.ErrorClass(in.String())
+		case "error":
+			out.Error = string(in.String())
+		case "judgement":
+			if in.IsNull() {
+				in.Skip()
+				out.Judgement = nil
+			} else {
+				if out.Judgement == nil {
+					out.Judgement = new(proxy.Judgement)
+				}
+				(*out.Judgement).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson15d5d517EncodeGithubComAlekcProxyStore(out *jwriter.Writer, in Record) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"proxy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(in.Proxy).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"time\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Time).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"latency\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Latency))
+	}
+	{
+		const prefix string = ",\"ok\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Ok))
+	}
+	if in.StatusCode != 0 {
+		const prefix string = ",\"status_code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.StatusCode))
+	}
+	if in.ErrorClass != "" {
+		const prefix string = ",\"error_class\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ErrorClass))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Error))
+	}
+	if in.Judgement != nil {
+		const prefix string = ",\"judgement\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Judgement).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Record) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson15d5d517EncodeGithubComAlekcProxyStore(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Record) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson15d5d517EncodeGithubComAlekcProxyStore(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Record) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson15d5d517DecodeGithubComAlekcProxyStore(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Record) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson15d5d517DecodeGithubComAlekcProxyStore(l, v)
+}
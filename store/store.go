@@ -0,0 +1,46 @@
+package store
+
+import (
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/proxy/tester"
+)
+
+//Store persists history of proxy checks
+type Store interface {
+	//Save appends a record to the history
+	Save(record *Record) error
+	//History returns all records of the proxy made since given time, oldest first
+	History(px *proxy.Proxy, since time.Time) ([]*Record, error)
+	//Latest returns the latest record of every known proxy
+	Latest() ([]*Record, error)
+	//AliveSince returns proxies which had at least one successful check since given time
+	AliveSince(since time.Time) ([]*proxy.Proxy, error)
+	//Uptime returns percentage (0..100) of successful checks of the proxy since given time
+	Uptime(px *proxy.Proxy, since time.Time) (float64, error)
+	Close() error
+}
+
+//NewRecord creates a record from the tester result
+func NewRecord(px *proxy.Proxy, result *tester.Result, checkedAt time.Time) *Record {
+	record := &Record{
+		Proxy: *px,
+		Time:  checkedAt,
+	}
+	if result == nil {
+		record.ErrorClass = tester.ErrorOther
+		return record
+	}
+	record.Ok = result.Ok
+	record.Latency = result.ExecTime
+	record.StatusCode = result.ResponseCode
+	record.ErrorClass = result.ErrorClass()
+	if result.Err != nil {
+		record.Error = result.Err.Error()
+	}
+	if result.Ok {
+		record.Judgement, _ = result.Judgement()
+	}
+	return record
+}
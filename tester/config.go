@@ -4,14 +4,6 @@ import "time"
 
 const version = "0.1"
 
-const (
-	TYPE_UNKNOWN = iota
-	TYPE_HTTP
-	TYPE_HTTPS
-	TYPE_SOCKS4
-	TYPE_SOCKS5
-)
-
 var DefaultConfig Config
 
 type Config struct {
@@ -0,0 +1,73 @@
+package tester
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+//ErrorClass groups check errors into broad categories
+type ErrorClass string
+
+//Known error classes
+const (
+	ErrorNone      ErrorClass = ""
+	ErrorTimeout   ErrorClass = "timeout"
+	ErrorRefused   ErrorClass = "refused"
+	ErrorReset     ErrorClass = "reset"
+	ErrorDNS       ErrorClass = "dns"
+	ErrorProxyAuth ErrorClass = "proxy_auth"
+	ErrorStatus    ErrorClass = "bad_status"
+	ErrorProtocol  ErrorClass = "protocol"
+	ErrorOther     ErrorClass = "other"
+)
+
+//ErrorClass returns the class of the error encountered during the check
+func (r *Result) ErrorClass() ErrorClass {
+	if r.Err == nil {
+		if r.Ok {
+			return ErrorNone
+		}
+		return ErrorOther
+	}
+	switch {
+	case r.ResponseCode == 407:
+		return ErrorProxyAuth
+	case r.ResponseCode != 0 && r.ResponseCode != 200:
+		return ErrorStatus
+	}
+	return ClassifyError(r.Err)
+}
+
+//ClassifyError returns the class of a network error
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorNone
+	}
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return ErrorTimeout
+	}
+	if _, ok := err.(*net.DNSError); ok {
+		return ErrorDNS
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		return ErrorTimeout
+	case strings.Contains(msg, "connection refused"):
+		return ErrorRefused
+	case strings.Contains(msg, "connection reset") || strings.Contains(msg, "broken pipe") || strings.Contains(msg, "eof"):
+		return ErrorReset
+	case strings.Contains(msg, "no such host"):
+		return ErrorDNS
+	case strings.Contains(msg, "proxy authentication required"):
+		return ErrorProxyAuth
+	case strings.Contains(msg, "malformed") || strings.Contains(msg, "socks") || strings.Contains(msg, "server does not"):
+		return ErrorProtocol
+	}
+	return ErrorOther
+}
@@ -0,0 +1,50 @@
+package tester
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err   error
+		class ErrorClass
+	}{
+		{nil, ErrorNone},
+		{context.DeadlineExceeded, ErrorTimeout},
+		{&url.Error{Op: "Post", URL: "http://example.com", Err: errors.New("net/http: request canceled (Client.Timeout exceeded)")}, ErrorTimeout},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, ErrorRefused},
+		{&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, ErrorReset},
+		{io.EOF, ErrorReset},
+		{&net.DNSError{Err: "no such host", Name: "invalid.example"}, ErrorDNS},
+		{errors.New("Proxy Authentication Required"), ErrorProxyAuth},
+		{errors.New("malformed HTTP response"), ErrorProtocol},
+		{errors.New("something else"), ErrorOther},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.class, ClassifyError(test.err), "%v", test.err)
+	}
+}
+
+func TestResult_ErrorClass(t *testing.T) {
+	tests := []struct {
+		result *Result
+		class  ErrorClass
+	}{
+		{&Result{Ok: true}, ErrorNone},
+		{&Result{}, ErrorOther},
+		{&Result{Err: errors.New("Invalid backend status code: [407]"), ResponseCode: 407}, ErrorProxyAuth},
+		{&Result{Err: errors.New("Invalid backend status code: [503]"), ResponseCode: 503}, ErrorStatus},
+		{&Result{Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}, ErrorRefused},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.class, test.result.ErrorClass(), "%+v", test.result)
+	}
+}
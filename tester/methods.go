@@ -3,19 +3,23 @@ package tester
 import (
 	"errors"
 	"fmt"
-	"github.com/alekc/socks"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/alekc/socks"
 )
 
 func TestHttp(Host string, Port int) (*Result, error) {
 	return DefaultTester.TestHttp(Host, Port)
 }
 func (ts *Tester) TestHttp(Host string, Port int) (*Result, error) {
-	proxyUrl, err := url.Parse(fmt.Sprintf("http://%s:%d", Host, Port))
+	proxyUrl, err := ProxyURL(&proxy.Proxy{Host: Host, Port: Port, Type: proxy.TypeHTTP})
 	if err != nil {
 		return nil, err
 	}
@@ -27,6 +31,25 @@ func (ts *Tester) TestHttp(Host string, Port int) (*Result, error) {
 	return result, nil
 }
 
+//ProxyURL returns url used by net/http to connect through the proxy. Both http and https proxies
+//are reached over plain http (https traffic is tunneled with CONNECT), socks4 proxies are not
+//supported by net/http.
+func ProxyURL(px *proxy.Proxy) (*url.URL, error) {
+	if px.Hostname() == "" || px.Port <= 0 {
+		return nil, errors.New("Host and/or port has not been set")
+	}
+	u := px.URL()
+	switch px.Type {
+	case proxy.TypeUnknown, proxy.TypeHTTP, proxy.TypeHTTPS:
+		u.Scheme = "http"
+	case proxy.TypeSocks5:
+		u.Scheme = "socks5"
+	default:
+		return nil, fmt.Errorf("proxy type %s is not supported by net/http", px.Type)
+	}
+	return u, nil
+}
+
 func TestSocks4(Host string, Port int) *Result {
 	return DefaultTester.TestSocks4(Host, Port)
 }
@@ -41,7 +64,7 @@ func (ts *Tester) TestSocks5(Host string, Port int) *Result {
 }
 
 func (ts *Tester) testSocks(Host string, Port, socksType int) *Result {
-	connectionString := fmt.Sprintf("%s:%s", Host, Port)
+	connectionString := net.JoinHostPort(Host, strconv.Itoa(Port))
 
 	//get the transport
 	dialSocksProxy := socks.DialSocksProxy(socksType, connectionString)
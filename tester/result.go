@@ -1,6 +1,11 @@
 package tester
 
-import "time"
+import (
+	"errors"
+	"time"
+
+	"github.com/alekc/proxy"
+)
 
 type Result struct {
 	Ok           bool
@@ -10,3 +15,15 @@ type Result struct {
 	ResponseCode int
 	ExecTime     time.Duration
 }
+
+//Judgement decodes judgement returned by the judge in the response body
+func (r *Result) Judgement() (*proxy.Judgement, error) {
+	if r.Body == "" {
+		return nil, errors.New("response body is empty")
+	}
+	judgement := &proxy.Judgement{}
+	if err := judgement.UnmarshalJSON([]byte(r.Body)); err != nil {
+		return nil, err
+	}
+	return judgement, nil
+}
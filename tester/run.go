@@ -1,10 +1,49 @@
 package tester
 
 import (
+	"net/http"
+	"time"
+
+	"github.com/alekc/proxy"
 	"github.com/pkg/errors"
 )
 
-func (ts *Tester) Check(Host string, Port int, ProxyType int) (*Result, error) {
+//CheckProxy checks given proxy object. Works with ipv4, ipv6 and hostname based proxies.
+//Credentials of the proxy are used for http, https and socks5 proxies.
+//If the Recorder is set, the result is recorded. In case of recording failure both result
+//and error are returned.
+func (ts *Tester) CheckProxy(px *proxy.Proxy) (*Result, error) {
+	if px == nil {
+		return nil, errors.New("proxy is nil")
+	}
+	checkedAt := time.Now()
+	result, err := ts.checkProxy(px)
+	if err != nil || ts.Recorder == nil {
+		return result, err
+	}
+	if err = ts.Recorder.Record(px, result, checkedAt); err != nil {
+		return result, errors.Wrap(err, "couldn't record result")
+	}
+	return result, nil
+}
+
+//checkProxy runs the check through the proxy url, so the credentials are sent to the proxy
+func (ts *Tester) checkProxy(px *proxy.Proxy) (*Result, error) {
+	switch {
+	case px.Type == proxy.TypeHTTP || px.Type == proxy.TypeHTTPS:
+	case px.Type == proxy.TypeSocks5 && px.Username != "":
+	default:
+		return ts.Check(px.Hostname(), px.Port, px.Type)
+	}
+	proxyURL, err := ProxyURL(px)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+	return ts.downloadWithTransport(httpClient, ts.Config.HttpUri), nil
+}
+
+func (ts *Tester) Check(Host string, Port int, ProxyType proxy.ProxyType) (*Result, error) {
 	if Host == "" || Port <= 0 {
 		return nil, errors.New("Host and/or port has not been set")
 	}
@@ -16,17 +55,15 @@ func (ts *Tester) Check(Host string, Port int, ProxyType int) (*Result, error) {
 	//}
 
 	switch ProxyType {
-	case TYPE_HTTP:
+	case proxy.TypeHTTP:
 		return ts.TestHttp(Host, Port)
-		break
-	case TYPE_HTTPS:
+	case proxy.TypeHTTPS:
 		return ts.TestHttp(Host, Port)
-		break
-	case TYPE_SOCKS4:
-		return ts.TestSocks4(Host, Port), nil
-	case TYPE_SOCKS5:
+	case proxy.TypeSocks4:
 		return ts.TestSocks4(Host, Port), nil
-	case TYPE_UNKNOWN:
+	case proxy.TypeSocks5:
+		return ts.TestSocks5(Host, Port), nil
+	case proxy.TypeUnknown:
 		//if result := ts.TestHttp("")
 	}
 	return nil, errors.New("Unknown proxy type")
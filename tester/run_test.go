@@ -0,0 +1,74 @@
+package tester
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alekc/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+//fakeRecorder keeps recorded results in memory
+type fakeRecorder struct {
+	proxies []*proxy.Proxy
+	results []*Result
+	err     error
+}
+
+func (r *fakeRecorder) Record(px *proxy.Proxy, result *Result, checkedAt time.Time) error {
+	r.proxies = append(r.proxies, px)
+	r.results = append(r.results, result)
+	return r.err
+}
+
+func TestTester_CheckProxy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//basic auth of user:pass
+		if r.Header.Get("Proxy-Authorization") != "Basic dXNlcjpwYXNz" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		_, _ = w.Write([]byte("ok " + r.URL.String()))
+	}))
+	defer server.Close()
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+
+	recorder := &fakeRecorder{}
+	ts := New()
+	ts.RealIp = "1.2.3.4"
+	ts.Config.HttpUri = "http://example.com/"
+	ts.Recorder = recorder
+
+	px := &proxy.Proxy{IP: net.ParseIP("127.0.0.1"), Port: port, Type: proxy.TypeHTTP, Username: "user", Password: "pass"}
+	result, err := ts.CheckProxy(px)
+	assert.NoError(t, err)
+	assert.True(t, result.Ok, "credentials should be sent to the proxy")
+	assert.Equal(t, "ok http://example.com/", result.Body)
+
+	anonymous := &proxy.Proxy{IP: net.ParseIP("127.0.0.1"), Port: port, Type: proxy.TypeHTTP}
+	result, err = ts.CheckProxy(anonymous)
+	assert.NoError(t, err)
+	assert.False(t, result.Ok)
+	assert.Equal(t, ErrorProxyAuth, result.ErrorClass())
+
+	assert.Equal(t, []*proxy.Proxy{px, anonymous}, recorder.proxies)
+	if assert.Len(t, recorder.results, 2) {
+		assert.True(t, recorder.results[0].Ok)
+		assert.False(t, recorder.results[1].Ok)
+	}
+
+	recorder.err = errors.New("disk full")
+	result, err = ts.CheckProxy(px)
+	assert.NotNil(t, result, "result should be returned on recording failure")
+	if assert.Error(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "disk full"))
+	}
+
+	_, err = ts.CheckProxy(nil)
+	assert.Error(t, err)
+}
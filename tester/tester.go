@@ -1,8 +1,21 @@
 package tester
 
+import (
+	"time"
+
+	"github.com/alekc/proxy"
+)
+
 type Tester struct {
 	Config Config
 	RealIp string
+	//Optional recorder receiving results of every CheckProxy call (see store package)
+	Recorder Recorder
+}
+
+//Recorder persists check results
+type Recorder interface {
+	Record(px *proxy.Proxy, result *Result, checkedAt time.Time) error
 }
 
 //Default Tester Instance
@@ -1,10 +1,10 @@
 package tester
 
 import (
-	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -31,7 +31,7 @@ func CheckIfPortOpen(Host string, Port int, TimeOut time.Duration) bool {
 	//configure timeout
 	dialer := net.Dialer{Timeout: TimeOut}
 
-	connectionString := fmt.Sprintf("%s:%d", Host, Port)
+	connectionString := net.JoinHostPort(Host, strconv.Itoa(Port))
 
 	//try to dial.
 	conn, err := dialer.Dial("tcp", connectionString)
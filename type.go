@@ -0,0 +1,97 @@
+package proxy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//ProxyType defines the protocol spoken by the proxy
+type ProxyType int
+
+//Known proxy types
+const (
+	TypeUnknown ProxyType = iota
+	TypeHTTP
+	TypeHTTPS
+	TypeSocks4
+	TypeSocks5
+)
+
+//proxyTypeNames contains canonical names of the proxy types. Names are also valid url schemes
+var proxyTypeNames = map[ProxyType]string{
+	TypeUnknown: "unknown",
+	TypeHTTP:    "http",
+	TypeHTTPS:   "https",
+	TypeSocks4:  "socks4",
+	TypeSocks5:  "socks5",
+}
+
+//proxyTypeAliases maps names and url schemes to proxy types
+var proxyTypeAliases = map[string]ProxyType{
+	"":        TypeUnknown,
+	"unknown": TypeUnknown,
+	"http":    TypeHTTP,
+	"https":   TypeHTTPS,
+	"socks4":  TypeSocks4,
+	"socks4a": TypeSocks4,
+	"socks5":  TypeSocks5,
+	"socks5h": TypeSocks5,
+}
+
+//ParseProxyType converts name or url scheme (i.e. socks5h) to a ProxyType. Matching is case insensitive.
+func ParseProxyType(name string) (ProxyType, error) {
+	if t, ok := proxyTypeAliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return t, nil
+	}
+	return TypeUnknown, fmt.Errorf("unknown proxy type: %s", name)
+}
+
+//String returns the name of the proxy type
+func (t ProxyType) String() string {
+	if name, ok := proxyTypeNames[t]; ok {
+		return name
+	}
+	return "ProxyType(" + strconv.Itoa(int(t)) + ")"
+}
+
+//IsValid returns true if the type is one of the known proxy types
+func (t ProxyType) IsValid() bool {
+	_, ok := proxyTypeNames[t]
+	return ok
+}
+
+//MarshalText implements encoding.TextMarshaler
+func (t ProxyType) MarshalText() ([]byte, error) {
+	if !t.IsValid() {
+		return nil, fmt.Errorf("invalid proxy type: %d", int(t))
+	}
+	return []byte(t.String()), nil
+}
+
+//UnmarshalText implements encoding.TextUnmarshaler. Numeric values are accepted as well.
+func (t *ProxyType) UnmarshalText(text []byte) error {
+	if n, err := strconv.Atoi(string(text)); err == nil {
+		if !ProxyType(n).IsValid() {
+			return fmt.Errorf("invalid proxy type: %d", n)
+		}
+		*t = ProxyType(n)
+		return nil
+	}
+	parsed, err := ParseProxyType(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+//UnmarshalJSON implements json.Unmarshaler. Both names ("socks5") and numbers (4) are accepted
+//in order to read json produced by older versions.
+func (t *ProxyType) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "null" {
+		return nil
+	}
+	return t.UnmarshalText([]byte(text))
+}
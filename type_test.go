@@ -0,0 +1,48 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProxyType(t *testing.T) {
+	for name, expected := range map[string]ProxyType{
+		"http":    TypeHTTP,
+		"HTTPS":   TypeHTTPS,
+		"socks4a": TypeSocks4,
+		"socks5h": TypeSocks5,
+		"":        TypeUnknown,
+	} {
+		pt, err := ParseProxyType(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, pt, "unexpected type for %s", name)
+	}
+	_, err := ParseProxyType("ftp")
+	assert.EqualError(t, err, "unknown proxy type: ftp")
+	assert.Equal(t, "socks5", TypeSocks5.String())
+	assert.Equal(t, "ProxyType(42)", ProxyType(42).String())
+}
+
+func TestProxyType_JSON(t *testing.T) {
+	px := &Proxy{IP: net.ParseIP("1.2.3.4"), Port: 1080, Type: TypeSocks5}
+	encoded, err := px.MarshalJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"ip":"1.2.3.4","port":1080,"type":"socks5"}`, string(encoded))
+
+	//standard library should produce the same result
+	encoded, err = json.Marshal(px)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"ip":"1.2.3.4","port":1080,"type":"socks5"}`, string(encoded))
+
+	//older numeric format
+	decoded := &Proxy{}
+	assert.NoError(t, decoded.UnmarshalJSON([]byte(`{"ip":"1.2.3.4","port":1080,"type":4}`)))
+	assert.Equal(t, px, decoded)
+	assert.NoError(t, decoded.UnmarshalJSON([]byte(`{"ip":"1.2.3.4","port":1080,"type":"socks5"}`)))
+	assert.Equal(t, px, decoded)
+
+	assert.Error(t, decoded.UnmarshalJSON([]byte(`{"ip":"1.2.3.4","port":1080,"type":"ftp"}`)))
+}
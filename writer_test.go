@@ -0,0 +1,113 @@
+package proxy
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testProxies() []*Proxy {
+	return []*Proxy{
+		{IP: net.ParseIP("1.2.3.4"), Port: 8080, Type: TypeHTTP},
+		{IP: net.ParseIP("2001:db8::1"), Port: 1080, Type: TypeSocks5, Username: "user", Password: "secret"},
+	}
+}
+
+func TestNewListWriter(t *testing.T) {
+	_, err := NewListWriter("unknown", &bytes.Buffer{}, false)
+	assert.EqualError(t, err, "unknown output format: unknown")
+	assert.Equal(t, []string{"csv", "jsonl", "pac", "plain", "proxychains", "squid", "url"}, ListWriterNames())
+}
+
+func TestListWriters(t *testing.T) {
+	expected := map[string]string{
+		"plain": "1.2.3.4:8080\n[2001:db8::1]:1080\n",
+		"url":   "http://1.2.3.4:8080\nsocks5://user:xxxxx@[2001:db8::1]:1080\n",
+		"csv": "ip,port,type,username,password,anon_type,country,remote_ip\n" +
+			"1.2.3.4,8080,http,,,,,\n" +
+			"2001:db8::1,1080,socks5,user,,,,\n",
+		"jsonl": `{"ip":"1.2.3.4","port":8080,"type":"http"}` + "\n" +
+			`{"ip":"2001:db8::1","port":1080,"type":"socks5","username":"user"}` + "\n",
+		"proxychains": "random_chain\nchain_len = 1\nproxy_dns\ntcp_read_time_out 15000\ntcp_connect_time_out 8000\n\n[ProxyList]\n" +
+			"http\t1.2.3.4\t8080\n" +
+			"# [2001:db8::1]:1080 requires authentication, credentials are omitted\n" +
+			"socks5\t2001:db8::1\t1080\n",
+		"pac": "function FindProxyForURL(url, host) {\n\treturn \"PROXY 1.2.3.4:8080; SOCKS5 [2001:db8::1]:1080\";\n}\n",
+		"squid": "cache_peer 1.2.3.4 parent 8080 0 no-query no-digest round-robin connect-fail-limit=2 name=proxy1\n" +
+			"# skipped socks5://user:xxxxx@[2001:db8::1]:1080: squid does not support socks peers\n" +
+			"never_direct allow all\n",
+	}
+	for name, output := range expected {
+		buf := &bytes.Buffer{}
+		lw, err := NewListWriter(name, buf, false)
+		assert.NoError(t, err)
+		assert.NoError(t, WriteList(lw, testProxies()))
+		assert.Equal(t, output, buf.String(), "unexpected output for %s", name)
+		assert.NotContains(t, buf.String(), "secret", "%s should not contain password", name)
+	}
+}
+
+func TestListWriters_Authentication(t *testing.T) {
+	px := &Proxy{Host: "proxy.example.com", Port: 3128, Type: TypeHTTP, Username: "user", Password: "secret"}
+	expected := map[string][]string{
+		"proxychains": {
+			"# proxy.example.com:3128 requires authentication, credentials are omitted\nhttp\tproxy.example.com\t3128\n",
+			"http\tproxy.example.com\t3128\tuser\tsecret\n",
+		},
+		"squid": {
+			"# proxy.example.com:3128 requires authentication, credentials are omitted\n" +
+				"cache_peer proxy.example.com parent 3128 0 no-query no-digest round-robin connect-fail-limit=2 name=proxy1\n",
+			"cache_peer proxy.example.com parent 3128 0 no-query no-digest round-robin connect-fail-limit=2 name=proxy1 login=user:secret\n",
+		},
+	}
+	for name, outputs := range expected {
+		for i, withCredentials := range []bool{false, true} {
+			buf := &bytes.Buffer{}
+			lw, err := NewListWriter(name, buf, withCredentials)
+			assert.NoError(t, err)
+			assert.NoError(t, WriteList(lw, []*Proxy{px}))
+			assert.Contains(t, buf.String(), outputs[i], "unexpected output for %s", name)
+		}
+	}
+}
+
+func TestListWriters_RoundTrip(t *testing.T) {
+	for _, name := range []string{"plain", "url", "csv", "jsonl"} {
+		buf := &bytes.Buffer{}
+		lw, err := NewListWriter(name, buf, true)
+		assert.NoError(t, err)
+		assert.NoError(t, WriteList(lw, testProxies()))
+
+		proxies, errs := NewListReader(strings.NewReader(buf.String()), FormatAuto).ReadAll()
+		assert.Empty(t, errs, "%s output should be readable", name)
+		if name == "plain" {
+			assert.Len(t, proxies, 2)
+			continue
+		}
+		assert.Equal(t, testProxies(), proxies, "%s round trip failed", name)
+	}
+}
+
+func TestListWriters_Judgement(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lw, _ := NewListWriter("jsonl", buf, false)
+	assert.NoError(t, lw.Write(ListEntry{
+		Proxy:     testProxies()[0],
+		Judgement: &Judgement{AnonType: AnonElite, Country: "DE", Messages: []string{}},
+	}))
+	assert.NoError(t, lw.Flush())
+	assert.Contains(t, buf.String(), `"judgement":{"anon_type":3,"messages":[]`)
+	assert.Contains(t, buf.String(), `"country":"DE"`)
+
+	buf.Reset()
+	lw, _ = NewListWriter("csv", buf, false)
+	assert.NoError(t, lw.Write(ListEntry{
+		Proxy:     testProxies()[0],
+		Judgement: &Judgement{AnonType: AnonElite, Country: "DE", RemoteIP: net.ParseIP("5.6.7.8")},
+	}))
+	assert.NoError(t, lw.Flush())
+	assert.Contains(t, buf.String(), "1.2.3.4,8080,http,,,elite,DE,5.6.7.8\n")
+}